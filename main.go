@@ -3,10 +3,13 @@ package main
 import (
 	"log"
 	"os"
+	"path/filepath"
 	"time"
 	"titik-rindang/src/database"
-	"titik-rindang/src/models"
+	"titik-rindang/src/payments"
 	"titik-rindang/src/routes"
+	"titik-rindang/src/services"
+	"titik-rindang/src/storage"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -18,19 +21,25 @@ func main() {
 		log.Println("Link Start!")
 	}
 
-	// DB connection
-	database.ConnectDB()
+	// DB connection, skema & perbaikan data lama dijalankan lewat database.Migrate
+	if _, err := database.ConnectDB(); err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+
+	// Role & permission bawaan (admin, cashier, staff)
+	if err := services.NewRoleService(database.DB).SeedRoles(); err != nil {
+		log.Printf("failed to seed roles: %v", err)
+	}
 
-	// Automigrate
-	database.DB.AutoMigrate(
-		&models.Auth{},
-		&models.Invoice{},
-		&models.Menu{},
-		&models.Order{},
-		&models.OrderItem{},
-		&models.Reservation{},
-		&models.Table{},
-	)
+	// production wajib memilih provider sendiri, fallback ke fake provider hanya untuk development
+	if gin.Mode() == gin.ReleaseMode && !payments.ProviderConfigured() {
+		log.Fatal("PAYMENT_PROVIDER must be set to a known provider when GIN_MODE=release")
+	}
+
+	// tanpa secret, callback payment ditolak (tidak ada secret default)
+	if !payments.WebhookSecretConfigured() {
+		log.Println("PAYMENT_WEBHOOK_SECRET is not set, payment webhooks will be rejected")
+	}
 
 	router := gin.Default()
 
@@ -50,9 +59,18 @@ func main() {
 	routes.TableRoutes(router)
 	routes.AuthRoutes(router)
 	routes.OrderRoutes(router)
+	routes.KitchenRoutes(router)
+	routes.EventRoutes(router)
+	routes.PaymentRoutes(router)
+	routes.InventoryRoutes(router)
+	routes.PurchaseRoutes(router)
 
-	router.Static("/uploads/menu", "./src/uploads/menu")
-	router.Static("/uploads/receipts", "./src/uploads/receipts")
+	// File upload hanya disajikan dari server kalau pakai storage lokal (S3 punya URL sendiri)
+	if local, ok := storage.Default().(*storage.LocalStorage); ok {
+		router.Static(local.BaseURL+"/menu", filepath.Join(local.Root, "menu"))
+		router.Static(local.BaseURL+"/receipts", filepath.Join(local.Root, "receipts"))
+		router.Static(local.BaseURL+"/purchase-orders", filepath.Join(local.Root, "purchase-orders"))
+	}
 
 	// Server berjalan di port 8080
 	port := os.Getenv("PORT")
@@ -0,0 +1,17 @@
+import React, { Suspense } from "react";
+import ResetPassword from "@/components/ResetPassword";
+
+export const metadata = {
+  title: "Reset Password - Titik Rindang",
+  description: "Reset password akun pengelola Titik Rindang.",
+};
+
+export default function ResetPasswordPage() {
+  return (
+    <main className="min-h-screen bg-gray-50">
+      <Suspense>
+        <ResetPassword />
+      </Suspense>
+    </main>
+  );
+}
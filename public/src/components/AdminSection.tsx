@@ -17,10 +17,15 @@ import {
   BarChart3,
   ClipboardList,
   FileText,
+  KeyRound,
+  Ban,
+  UserCheck,
+  Unlock,
 } from "lucide-react";
 
 import Swal from "sweetalert2";
 import "sweetalert2/dist/sweetalert2.min.css";
+import { logout } from "@/lib/authFetch";
 
 function UserRegisterForm({
   token,
@@ -32,6 +37,23 @@ function UserRegisterForm({
   const [email, setEmail] = useState("");
   const [password, setPassword] = useState("");
   const [role, setRole] = useState("staff");
+  const [roleOptions, setRoleOptions] = useState<string[]>(["staff", "cashier"]);
+
+  // 🔹 Daftar role dari backend (termasuk role custom)
+  useEffect(() => {
+    if (!token) return;
+    fetch("http://localhost:8080/admin/roles", {
+      headers: { Authorization: `Bearer ${token}` },
+    })
+      .then((res) => (res.ok ? res.json() : null))
+      .then((data) => {
+        const names = (data?.roles ?? [])
+          .map((r: any) => r.Name)
+          .filter((name: string) => name !== "admin");
+        if (names.length > 0) setRoleOptions(names);
+      })
+      .catch(() => {});
+  }, [token]);
 
   useEffect(() => {
     if (editingUser) {
@@ -148,8 +170,11 @@ function UserRegisterForm({
         onChange={(e) => setRole(e.target.value)}
         className="border text-gray-800 border-gray-600 rounded-lg p-2 flex-1 min-w-[150px] focus:ring-2 focus:ring-emerald-500 outline-none"
       >
-        <option value="staff">Staff</option>
-        <option value="cashier">Cashier</option>
+        {roleOptions.map((name) => (
+          <option key={name} value={name}>
+            {name.charAt(0).toUpperCase() + name.slice(1)}
+          </option>
+        ))}
       </select>
 
       {/* 🔹 Tombol aksi */}
@@ -189,6 +214,9 @@ interface User {
   id: string;
   username: string;
   role: string;
+  resetAllowed?: boolean;
+  status?: string;
+  lockedUntil?: string | null;
 }
 
 interface Reservation {
@@ -319,8 +347,8 @@ export default function AdminSection() {
 
   const router = useRouter();
 
-  const handleLogout = () => {
-    localStorage.removeItem("token"); // hapus token
+  const handleLogout = async () => {
+    await logout(); // cabut sesi di backend + hapus token
     Swal.fire("Berhasil!", "Logout berhasil!", "success");
 
     router.push("/"); // redirect ke homepage
@@ -503,6 +531,9 @@ export default function AdminSection() {
         id: u.ID || u.id,
         username: u.Username || u.username,
         role: u.Role || u.role,
+        resetAllowed: u.ResetAllowed ?? false,
+        status: u.Status || "active",
+        lockedUntil: u.LockedUntil ?? null,
       }));
 
       setUsers(formatted);
@@ -568,6 +599,79 @@ export default function AdminSection() {
     }
   };
 
+  // 🔑 Izinkan / cabut izin reset password user
+  const toggleResetAllowed = async (user: User) => {
+    const allowed = !user.resetAllowed;
+    try {
+      const res = await fetch(
+        `http://localhost:8080/admin/users/${user.id}/reset-allowed`,
+        {
+          method: "PUT",
+          headers: {
+            "Content-Type": "application/json",
+            Authorization: `Bearer ${token}`,
+          },
+          body: JSON.stringify({ allowed }),
+        }
+      );
+      if (!res.ok) throw new Error();
+
+      setUsers((prev) =>
+        prev.map((u) => (u.id === user.id ? { ...u, resetAllowed: allowed } : u))
+      );
+      Swal.fire(
+        "Berhasil!",
+        allowed
+          ? `${user.username} sekarang bisa reset password lewat email.`
+          : `Izin reset password ${user.username} dicabut.`,
+        "success"
+      );
+    } catch (err) {
+      Swal.fire("Gagal", "Gagal mengubah izin reset password.", "error");
+    }
+  };
+
+  // 🚫 Suspend / aktifkan lagi / buka kunci akun
+  const isLocked = (user: User) =>
+    !!user.lockedUntil && new Date(user.lockedUntil) > new Date();
+
+  const userAction = async (
+    user: User,
+    action: "suspend" | "reactivate" | "unlock"
+  ) => {
+    const labels = {
+      suspend: `Suspend ${user.username}? User langsung ter-logout.`,
+      reactivate: `Aktifkan lagi ${user.username}?`,
+      unlock: `Buka kunci akun ${user.username}?`,
+    };
+    const sure = await Swal.fire({
+      title: "Konfirmasi",
+      text: labels[action],
+      icon: "question",
+      showCancelButton: true,
+      confirmButtonText: "Ya",
+      cancelButtonText: "Batal",
+    });
+    if (!sure.isConfirmed) return;
+
+    try {
+      const res = await fetch(
+        `http://localhost:8080/admin/users/${user.id}/${action}`,
+        {
+          method: "PUT",
+          headers: { Authorization: `Bearer ${token}` },
+        }
+      );
+      const data = await res.json().catch(() => ({}));
+      if (!res.ok) throw new Error(data.error);
+
+      await fetchUsers();
+      Swal.fire("Berhasil!", data.message, "success");
+    } catch (err: any) {
+      Swal.fire("Gagal", err?.message || "Gagal mengubah status user.", "error");
+    }
+  };
+
   // ===== RESERVATION MANAGEMENT (Admin) =====
   // 🔹 Fetch semua reservasi
   const fetchReservations = async () => {
@@ -700,7 +804,7 @@ export default function AdminSection() {
       }
 
       const receiptPath = raw.receipt;
-      const cleanPath = receiptPath.replace(/^src\//, "");
+      const cleanPath = receiptPath.replace(/^\/?(src\/)?/, "");
       const pdfUrl = cleanPath.startsWith("http")
         ? cleanPath
         : `http://localhost:8080/${cleanPath}`;
@@ -1629,6 +1733,7 @@ export default function AdminSection() {
                           Username
                         </th>
                         <th className="p-4 text-left font-semibold">Role</th>
+                        <th className="p-4 text-left font-semibold">Status</th>
                         <th className="p-4 text-center font-semibold">
                           Actions
                         </th>
@@ -1638,7 +1743,7 @@ export default function AdminSection() {
                       {loadingUsers ? (
                         <tr>
                           <td
-                            colSpan={4}
+                            colSpan={5}
                             className="text-center py-6 text-gray-500"
                           >
                             🔄 Memuat data user...
@@ -1647,7 +1752,7 @@ export default function AdminSection() {
                       ) : users.length === 0 ? (
                         <tr>
                           <td
-                            colSpan={4}
+                            colSpan={5}
                             className="text-center py-6 text-gray-500"
                           >
                             Belum ada user terdaftar.
@@ -1678,6 +1783,22 @@ export default function AdminSection() {
                                 {user.role}
                               </span>
                             </td>
+                            <td className="p-4">
+                              <span
+                                className={`px-3 py-1 rounded-full text-xs font-semibold ${
+                                  user.status === "active"
+                                    ? "bg-emerald-100 text-emerald-700"
+                                    : "bg-red-100 text-red-700"
+                                }`}
+                              >
+                                {user.status}
+                              </span>
+                              {isLocked(user) && (
+                                <span className="ml-2 px-3 py-1 rounded-full text-xs font-semibold bg-gray-200 text-gray-700">
+                                  🔒 terkunci
+                                </span>
+                              )}
+                            </td>
                             <td className="p-4 text-center flex items-center justify-center gap-2">
                               {user.role !== "admin" && (
                                 <>
@@ -1703,6 +1824,59 @@ export default function AdminSection() {
                                     <Edit size={18} />
                                   </button>
 
+                                  <button
+                                    onClick={() =>
+                                      userAction(
+                                        user,
+                                        user.status === "active"
+                                          ? "suspend"
+                                          : "reactivate"
+                                      )
+                                    }
+                                    title={
+                                      user.status === "active"
+                                        ? "Suspend user"
+                                        : "Aktifkan lagi"
+                                    }
+                                    className={`p-2 rounded-lg transition-all ${
+                                      user.status === "active"
+                                        ? "text-gray-500 hover:text-red-600 hover:bg-red-50"
+                                        : "text-emerald-600 hover:bg-emerald-50"
+                                    }`}
+                                  >
+                                    {user.status === "active" ? (
+                                      <Ban size={18} />
+                                    ) : (
+                                      <UserCheck size={18} />
+                                    )}
+                                  </button>
+
+                                  {isLocked(user) && (
+                                    <button
+                                      onClick={() => userAction(user, "unlock")}
+                                      title="Buka kunci akun"
+                                      className="text-gray-600 hover:text-gray-800 hover:bg-gray-100 p-2 rounded-lg transition-all"
+                                    >
+                                      <Unlock size={18} />
+                                    </button>
+                                  )}
+
+                                  <button
+                                    onClick={() => toggleResetAllowed(user)}
+                                    title={
+                                      user.resetAllowed
+                                        ? "Cabut izin reset password"
+                                        : "Izinkan reset password"
+                                    }
+                                    className={`p-2 rounded-lg transition-all ${
+                                      user.resetAllowed
+                                        ? "text-amber-600 bg-amber-50 hover:bg-amber-100"
+                                        : "text-gray-500 hover:text-amber-600 hover:bg-amber-50"
+                                    }`}
+                                  >
+                                    <KeyRound size={18} />
+                                  </button>
+
                                   <button
                                     onClick={() => deleteUser(user.id)}
                                     className="text-red-500 hover:text-red-700 hover:bg-red-50 p-2 rounded-lg transition-all"
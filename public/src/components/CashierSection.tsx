@@ -17,6 +17,7 @@ import {
 
 import Swal from "sweetalert2";
 import "sweetalert2/dist/sweetalert2.min.css";
+import { logout } from "@/lib/authFetch";
 
 // ===== Interface Definitions =====
 interface MenuItem {
@@ -232,7 +233,7 @@ export default function CashierSection() {
 
   // ===== LOGOUT =====
   const handleLogout = async () => {
-    localStorage.removeItem("token");
+    await logout(); // cabut sesi di backend + hapus token
     await Swal.fire("Logout", "Anda telah keluar ✅", "success");
 
     router.push("/");
@@ -423,7 +424,7 @@ export default function CashierSection() {
       }
 
       // ✅ Buat URL penuh (pastikan bisa diakses)
-      const cleanPath = receiptPath.replace(/^src\//, ""); // hapus 'src/' dari path
+      const cleanPath = receiptPath.replace(/^\/?(src\/)?/, ""); // hapus '/' atau 'src/' di depan path
       const pdfUrl = cleanPath.startsWith("http")
         ? cleanPath
         : `http://localhost:8080/${cleanPath}`;
@@ -3,6 +3,7 @@
 import { usePathname } from "next/navigation";
 import Header from "@/components/Header";
 import Footer from "@/components/Footer";
+import "@/lib/authFetch"; // 🔄 auto refresh access token
 
 export default function HeaderWrapper({
   children,
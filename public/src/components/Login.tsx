@@ -34,10 +34,16 @@ const Login = () => {
 
       if (response.ok) {
         localStorage.setItem("token", data.token);
+        localStorage.setItem("refresh_token", data.refresh_token);
 
+        // role custom diarahkan sesuai permission-nya
+        const permissions: string[] = data.permissions ?? [];
         if (data.role === "admin") router.push("/admin");
         else if (data.role === "staff") router.push("/staff");
         else if (data.role === "cashier") router.push("/cashier");
+        else if (permissions.includes("user.manage")) router.push("/admin");
+        else if (permissions.includes("order.pay")) router.push("/cashier");
+        else if (permissions.includes("kitchen.view")) router.push("/staff");
         else setError("Role tidak dikenali, hubungi admin.");
       } else {
         setError(data.error || "Username atau password salah.");
@@ -117,6 +123,12 @@ const Login = () => {
           >
             {isLoading ? "Loading..." : "Masuk"}
           </button>
+
+          <p className="text-center text-sm">
+            <a href="/reset-password" className="text-green-800 hover:underline">
+              Lupa password?
+            </a>
+          </p>
         </form>
       </div>
     </main>
@@ -27,6 +27,11 @@ export interface APIMenu {
   Tagline?: string;
   image_url?: string;
   ImageURL?: string;
+  Images?: { thumb?: string; card?: string; full?: string };
+  IsAvailable?: boolean;
+  AvailableNow?: boolean;
+  AvailableFrom?: string;
+  AvailableUntil?: string;
 }
 
 interface MenuItem {
@@ -35,6 +40,8 @@ interface MenuItem {
   price: string; // formatted like "25.000"
   description: string;
   image: string;
+  available: boolean;
+  availabilityNote: string; // "Habis" / "Tersedia 07:00-11:00"
 }
 
 interface MenuCategory {
@@ -68,6 +75,15 @@ interface BackendTable {
 const IMAGE_SRC = "/images/DenahMeja.png";
 const STORAGE_KEY = "table-map-statuses-v1";
 
+// Label menu yang tidak bisa dipesan sekarang
+const availabilityNote = (m: APIMenu): string => {
+  if (m.AvailableNow !== false) return "";
+  if (m.IsAvailable !== false && (m.AvailableFrom || m.AvailableUntil)) {
+    return `Tersedia ${m.AvailableFrom || "00:00"}-${m.AvailableUntil || "24:00"}`;
+  }
+  return "Habis";
+};
+
 const DEFAULT_TABLES: TableMarker[] = [
   {
     id: "I6-1",
@@ -205,6 +221,8 @@ const MenuSection: React.FC = () => {
   >({});
   const [backendTables, setBackendTables] = useState<BackendTable[]>([]);
   const [currentOrderId, setCurrentOrderId] = useState<number | null>(null);
+  const [trackingToken, setTrackingToken] = useState<string | null>(null);
+  const [paymentRef, setPaymentRef] = useState<string | null>(null);
   const [customerName, setCustomerName] = useState<string>("");
 
   const sectionRef = useRef<HTMLElement | null>(null);
@@ -284,6 +302,7 @@ const MenuSection: React.FC = () => {
 
         const mapped: MenuItem[] = raw.map((m) => {
           const rawPath =
+            m.Images?.card ??
             (m as any).image_url ??
             (m as any).ImageURL ??
             (m as any).image ??
@@ -297,9 +316,11 @@ const MenuSection: React.FC = () => {
             .trim();
 
           // 🔗 Bangun URL final
-          const finalImage = clean.includes("/uploads/")
-            ? `${API_BASE}/${clean.replace(/^\/+/, "")}`
-            : `${API_BASE}/uploads/menu/${clean}`;
+          const finalImage = /^https?:\/\//.test(clean)
+            ? clean
+            : clean.includes("/uploads/")
+              ? `${API_BASE}/${clean.replace(/^\/+/, "")}`
+              : `${API_BASE}/uploads/menu/${clean}`;
 
           return {
             id: (m as any).id ?? (m as any).ID ?? 0,
@@ -309,6 +330,8 @@ const MenuSection: React.FC = () => {
             ).toLocaleString("id-ID"),
             description: (m as any).tagline ?? (m as any).Tagline ?? "",
             image: finalImage,
+            available: m.AvailableNow ?? true,
+            availabilityNote: availabilityNote(m),
           };
         });
 
@@ -409,6 +432,7 @@ const MenuSection: React.FC = () => {
 
   // ---------- Cart handlers ----------
   const addToCart = (item: MenuItem) => {
+    if (!item.available) return;
     setCart((prev) => {
       const existing = prev.find((i) => i.menu_id === item.id);
       if (existing) {
@@ -598,6 +622,9 @@ const MenuSection: React.FC = () => {
       }
       const created = j?.data;
       setCurrentOrderId(created?.id ?? created?.ID ?? null);
+      // token dipakai customer untuk tracking & bayar tanpa login
+      setTrackingToken(created?.TrackingToken ?? null);
+      setPaymentRef(null);
 
       // After creating order, show confirmation modal that includes "Konfirmasi Pembayaran" button
       setShowPaymentModal(false);
@@ -626,9 +653,19 @@ const MenuSection: React.FC = () => {
     }
   };
 
-  // PUT /order/:id/confirm with { payment_method }
+  const finishCheckout = () => {
+    setShowConfirmation(false);
+    setCurrentOrderId(null);
+    setTrackingToken(null);
+    setPaymentRef(null);
+    setCart([]);
+    setSelectedTable(null);
+    setPaymentMethod("");
+  };
+
+  // Cash dibayar di kasir. E-Wallet lewat POST /payments/order/:token, lalu cek GET /payments/:ref
   const handleConfirmPayment = async () => {
-    if (!currentOrderId) {
+    if (!currentOrderId || !trackingToken) {
       Swal.fire({
         icon: "error",
         title: "Tidak Ada Pesanan",
@@ -648,20 +685,70 @@ const MenuSection: React.FC = () => {
 
       return;
     }
-    try {
-      const res = await fetch(`${API_BASE}/order/${currentOrderId}/confirm`, {
-        method: "PUT",
-        headers: { "Content-Type": "application/json" },
-        body: JSON.stringify({ payment_method: paymentMethod }),
+
+    if (paymentMethod === "Cash") {
+      const orderId = currentOrderId;
+      finishCheckout();
+      Swal.fire({
+        icon: "success",
+        title: "Pesanan Diterima",
+        text: `Silakan bayar tunai di kasir setelah pesanan diantar. Sebutkan nomor pesanan #${orderId}.`,
+        confirmButtonColor: "#166534",
       });
+      await refreshTablesFromBackend();
+      return;
+    }
+
+    try {
+      let ref = paymentRef;
+      if (!ref) {
+        const res = await fetch(`${API_BASE}/payments/order/${trackingToken}`, {
+          method: "POST",
+          headers: { "Content-Type": "application/json" },
+          body: JSON.stringify({ method: "ewallet" }),
+        });
+        const j = await res.json();
+        if (!res.ok) {
+          // order baru bisa dibayar setelah diantar
+          Swal.fire({
+            icon: "info",
+            title: "Belum Bisa Dibayar",
+            text: `Pembayaran bisa dilakukan setelah pesanan diantar. (${j?.message ?? "order belum siap"})`,
+            confirmButtonColor: "#166534",
+          });
+          return;
+        }
+        ref = j?.data?.ProviderRef as string;
+        setPaymentRef(ref);
+        Swal.fire({
+          icon: "info",
+          title: "Selesaikan Pembayaran",
+          html: j?.data?.RedirectURL
+            ? `Lanjutkan pembayaran di <a href="${j.data.RedirectURL}" target="_blank" rel="noopener noreferrer" class="underline">halaman e-wallet</a>, lalu klik "Konfirmasi dan Bayar" lagi.`
+            : `Scan QR pembayaran, lalu klik "Konfirmasi dan Bayar" lagi.`,
+          confirmButtonColor: "#166534",
+        });
+        return;
+      }
+
+      const res = await fetch(`${API_BASE}/payments/${ref}`);
       const j = await res.json();
       if (!res.ok) throw new Error(j?.message || JSON.stringify(j));
-      // success -> close modals, clear cart, clear current order
-      setShowConfirmation(false);
-      setCurrentOrderId(null);
-      setCart([]);
-      setSelectedTable(null);
-      setPaymentMethod("");
+
+      if (j?.data?.Status !== "paid") {
+        if (j?.data?.Status === "expired" || j?.data?.Status === "failed") {
+          setPaymentRef(null); // buat tagihan baru di klik berikutnya
+        }
+        Swal.fire({
+          icon: "info",
+          title: "Pembayaran Belum Diterima",
+          text: "Selesaikan pembayaran lalu klik konfirmasi lagi.",
+          confirmButtonColor: "#166534",
+        });
+        return;
+      }
+
+      finishCheckout();
       Swal.fire({
         icon: "success",
         title: "Pembayaran Berhasil",
@@ -813,6 +900,8 @@ const MenuSection: React.FC = () => {
               <div
                 key={item.id}
                 className={`group bg-white rounded-2xl overflow-hidden shadow-sm hover:shadow-2xl transition-all duration-500 hover:-translate-y-2 ${
+                  item.available ? "" : "grayscale opacity-60"
+                } ${
                   isVisible
                     ? "opacity-100 translate-y-0"
                     : "opacity-0 translate-y-8"
@@ -825,6 +914,11 @@ const MenuSection: React.FC = () => {
                     alt={item.name}
                     className="w-full h-full object-cover transition-transform duration-500 group-hover:scale-110"
                   />
+                  {!item.available && (
+                    <span className="absolute top-3 left-3 bg-gray-900/80 text-white text-xs font-semibold px-3 py-1 rounded-full">
+                      {item.availabilityNote}
+                    </span>
+                  )}
                 </div>
                 <div className="p-5">
                   <h3 className="text-lg font-bold text-gray-900 mb-2 group-hover:text-green-800 transition-colors duration-300">
@@ -841,9 +935,10 @@ const MenuSection: React.FC = () => {
                     </div>
                     <button
                       onClick={() => addToCart(item)}
-                      className="bg-green-800 hover:bg-green-900 text-white px-5 py-2 rounded-lg text-sm font-semibold transition-all duration-300"
+                      disabled={!item.available}
+                      className="bg-green-800 hover:bg-green-900 text-white px-5 py-2 rounded-lg text-sm font-semibold transition-all duration-300 disabled:bg-gray-400 disabled:cursor-not-allowed"
                     >
-                      Pesan
+                      {item.available ? "Pesan" : "Habis"}
                     </button>
                   </div>
                 </div>
@@ -1384,7 +1479,7 @@ const MenuSection: React.FC = () => {
                 <div>
                   <div className="font-semibold">E-Wallet</div>
                   <div className="text-xs text-gray-500">
-                    Transfer/scan QR (dummy)
+                    Bayar online setelah pesanan diantar
                   </div>
                 </div>
                 <input
@@ -1444,6 +1539,8 @@ const MenuSection: React.FC = () => {
                 onClick={() => {
                   setShowConfirmation(false);
                   setCurrentOrderId(null);
+                  setTrackingToken(null);
+                  setPaymentRef(null);
                 }}
                 className="flex-1 py-3 rounded-xl bg-red-700 hover:bg-red-800 border border-gray-200"
               >
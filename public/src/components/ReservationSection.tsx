@@ -165,8 +165,9 @@ const ReservationSection = () => {
   const [imageLoaded, setImageLoaded] = useState(false);
   const [isVisible, setIsVisible] = useState(false);
   const [currentDateTime, setCurrentDateTime] = useState(new Date());
-  const [reservationId, setReservationId] = useState<number | null>(null);
+  const [paymentToken, setPaymentToken] = useState<string | null>(null);
   const [reservationFee, setReservationFee] = useState<number>(0);
+  const [paymentRef, setPaymentRef] = useState<string | null>(null);
 
   const imgRef = useRef<HTMLImageElement | null>(null);
   const containerRef = useRef<HTMLDivElement | null>(null);
@@ -466,6 +467,7 @@ const ReservationSection = () => {
         phone: formData.phone,
         email: formData.email,
         table_id: selectedTable.backendId,
+        party_size: Number(formData.guests),
         reservation_date: reservationDateTime.toISOString(),
       };
 
@@ -480,7 +482,8 @@ const ReservationSection = () => {
       const data = await response.json();
 
       if (data.status === "success") {
-        setReservationId(data.data.ID);
+        setPaymentToken(data.data.PaymentToken ?? null);
+        setPaymentRef(null);
         setShowPaymentModal(true);
       } else {
         throw new Error(data.message || "Failed to create reservation");
@@ -494,23 +497,43 @@ const ReservationSection = () => {
   };
 
   const confirmPayment = async () => {
-    if (!reservationId) {
-      alert("Reservation ID tidak ditemukan");
+    if (!paymentToken) {
+      alert("Reservasi tidak ditemukan");
       return;
     }
 
     setLoading(true);
 
     try {
-      const response = await fetch(
-        `${API_BASE_URL}/reservation/confirm/${reservationId}`,
-        {
-          method: "POST",
+      // reservasi hanya lunas lewat callback payment provider, cek status tagihannya
+      let ref = paymentRef;
+      if (!ref) {
+        const createResponse = await fetch(
+          `${API_BASE_URL}/payments/reservation/${paymentToken}`,
+          {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ method: "qris" }),
+          }
+        );
+        const created = await createResponse.json();
+        if (created.status !== "success") {
+          throw new Error(created.message || "Failed to create payment");
         }
-      );
+        ref = created.data.ProviderRef as string;
+        setPaymentRef(ref);
+      }
 
+      const response = await fetch(`${API_BASE_URL}/payments/${ref}`);
       const data = await response.json();
 
+      if (data.status === "success" && data.data.Status !== "paid") {
+        alert(
+          "Pembayaran belum diterima. Selesaikan pembayaran lalu klik konfirmasi lagi."
+        );
+        return;
+      }
+
       if (data.status === "success") {
         setPaymentConfirmed(true);
         setShowPaymentModal(false);
@@ -551,7 +574,8 @@ const ReservationSection = () => {
     setCurrentStep(1);
     setSelectedTable(null);
     setPaymentConfirmed(false);
-    setReservationId(null);
+    setPaymentToken(null);
+    setPaymentRef(null);
     setFormData({
       name: "",
       email: "",
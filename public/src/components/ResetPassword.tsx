@@ -0,0 +1,144 @@
+"use client";
+
+import React, { useState } from "react";
+import { useRouter, useSearchParams } from "next/navigation";
+import { KeyRound } from "lucide-react";
+
+const API_BASE = "http://localhost:8080";
+
+const inputClass =
+  "w-full border border-gray-300 text-gray-700 rounded-lg px-4 py-2 focus:outline-none focus:ring-2 focus:ring-green-700 focus:border-transparent";
+
+// 🔑 Tanpa ?token= : minta link reset lewat email. Dengan token: set password baru.
+const ResetPassword = () => {
+  const router = useRouter();
+  const token = useSearchParams().get("token");
+
+  const [email, setEmail] = useState("");
+  const [password, setPassword] = useState("");
+  const [confirm, setConfirm] = useState("");
+  const [message, setMessage] = useState("");
+  const [error, setError] = useState("");
+  const [isLoading, setIsLoading] = useState(false);
+
+  const post = async (path: string, body: object) => {
+    setError("");
+    setMessage("");
+    setIsLoading(true);
+    try {
+      const res = await fetch(`${API_BASE}${path}`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify(body),
+      });
+      const data = await res.json();
+      if (!res.ok) {
+        setError(data.error || "Terjadi kesalahan, coba lagi.");
+        return false;
+      }
+      setMessage(data.message);
+      return true;
+    } catch {
+      setError("Gagal terhubung ke server. Coba lagi nanti.");
+      return false;
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  const handleRequest = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (await post("/auth/forgot-password", { email })) {
+      setMessage(
+        "Kalau akun Anda sudah diizinkan admin, link reset password telah dikirim ke email."
+      );
+    }
+  };
+
+  const handleReset = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (password !== confirm) {
+      setError("Konfirmasi password tidak sama.");
+      return;
+    }
+    if (await post("/auth/reset-password", { token, password })) {
+      setTimeout(() => router.push("/login"), 1500);
+    }
+  };
+
+  return (
+    <main className="min-h-screen flex items-center justify-center bg-gradient-to-b from-green-50 to-white">
+      <div className="w-full max-w-md bg-white rounded-2xl shadow-xl p-8 space-y-6 mt-20">
+        <div className="flex flex-col items-center space-y-2">
+          <div className="w-12 h-12 rounded-full bg-green-800 flex items-center justify-center">
+            <KeyRound className="text-white" size={24} />
+          </div>
+          <h1 className="text-2xl font-bold text-green-800">Reset Password</h1>
+          <p className="text-gray-500 text-sm text-center">
+            {token
+              ? "Masukkan password baru Anda"
+              : "Reset password harus diizinkan admin terlebih dahulu"}
+          </p>
+        </div>
+
+        {token ? (
+          <form onSubmit={handleReset} className="space-y-4">
+            <input
+              type="password"
+              value={password}
+              onChange={(e) => setPassword(e.target.value)}
+              placeholder="Password baru"
+              className={inputClass}
+              required
+              disabled={isLoading}
+            />
+            <input
+              type="password"
+              value={confirm}
+              onChange={(e) => setConfirm(e.target.value)}
+              placeholder="Ulangi password baru"
+              className={inputClass}
+              required
+              disabled={isLoading}
+            />
+            <p className="text-xs text-gray-500">
+              Minimal 8 karakter, ada huruf besar, huruf kecil dan angka.
+            </p>
+            {error && <p className="text-red-600 text-sm text-center">{error}</p>}
+            {message && <p className="text-green-700 text-sm text-center">{message}</p>}
+            <button
+              type="submit"
+              className="w-full bg-green-800 text-white py-2 rounded-lg hover:bg-green-700 transition-colors font-semibold disabled:bg-green-400"
+              disabled={isLoading}
+            >
+              {isLoading ? "Loading..." : "Simpan Password"}
+            </button>
+          </form>
+        ) : (
+          <form onSubmit={handleRequest} className="space-y-4">
+            <input
+              type="email"
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              placeholder="Email akun"
+              className={inputClass}
+              required
+              disabled={isLoading}
+            />
+            {error && <p className="text-red-600 text-sm text-center">{error}</p>}
+            {message && <p className="text-green-700 text-sm text-center">{message}</p>}
+            <button
+              type="submit"
+              className="w-full bg-green-800 text-white py-2 rounded-lg hover:bg-green-700 transition-colors font-semibold disabled:bg-green-400"
+              disabled={isLoading}
+            >
+              {isLoading ? "Loading..." : "Kirim Link Reset"}
+            </button>
+          </form>
+        )}
+      </div>
+    </main>
+  );
+};
+
+export default ResetPassword;
@@ -17,6 +17,7 @@ import {
 
 import Swal from "sweetalert2";
 import "sweetalert2/dist/sweetalert2.min.css";
+import { logout } from "@/lib/authFetch";
 
 // ===== Type definition =====
 interface MenuItem {
@@ -256,7 +257,7 @@ export default function StaffSection() {
 
   // ===== Logout =====
   const handleLogout = async () => {
-    localStorage.removeItem("token");
+    await logout(); // cabut sesi di backend + hapus token
     await Swal.fire("Logout", "Anda telah keluar ✅", "success");
 
     router.push("/");
@@ -0,0 +1,83 @@
+// 🔄 Access token backend cuma berlaku sebentar. Semua request ke API yang
+// membawa Bearer token otomatis di-refresh (pakai refresh_token) saat dapat 401,
+// lalu diulang sekali, supaya kasir tidak ter-logout di tengah shift.
+const API_BASE = "http://localhost:8080";
+
+let refreshing: Promise<string | null> | null = null;
+
+const refreshAccessToken = (originalFetch: typeof fetch) => {
+  if (!refreshing) {
+    refreshing = (async () => {
+      const refreshToken = localStorage.getItem("refresh_token");
+      if (!refreshToken) return null;
+
+      const res = await originalFetch(`${API_BASE}/auth/refresh`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      });
+      if (!res.ok) {
+        localStorage.removeItem("token");
+        localStorage.removeItem("refresh_token");
+        return null;
+      }
+
+      const data = await res.json();
+      localStorage.setItem("token", data.token);
+      localStorage.setItem("refresh_token", data.refresh_token);
+      return data.token as string;
+    })().finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+};
+
+if (typeof window !== "undefined" && !(window as any).__authFetchInstalled) {
+  (window as any).__authFetchInstalled = true;
+  const originalFetch = window.fetch.bind(window);
+
+  window.fetch = async (input: RequestInfo | URL, init?: RequestInit) => {
+    const url =
+      typeof input === "string"
+        ? input
+        : input instanceof URL
+          ? input.href
+          : input.url;
+    const headers = new Headers(init?.headers);
+    const isApiCall =
+      url.startsWith(API_BASE) &&
+      !url.startsWith(`${API_BASE}/auth/login`) &&
+      !url.startsWith(`${API_BASE}/auth/refresh`);
+
+    if (!isApiCall || !headers.get("Authorization")?.startsWith("Bearer ")) {
+      return originalFetch(input, init);
+    }
+
+    // selalu pakai token terbaru, komponen bisa saja masih pegang token lama
+    const current = localStorage.getItem("token");
+    if (current) headers.set("Authorization", `Bearer ${current}`);
+
+    const res = await originalFetch(input, { ...init, headers });
+    if (res.status !== 401) return res;
+
+    const token = await refreshAccessToken(originalFetch);
+    if (!token) return res;
+
+    headers.set("Authorization", `Bearer ${token}`);
+    return originalFetch(input, { ...init, headers });
+  };
+}
+
+// Logout: cabut sesi di backend lalu hapus token lokal
+export const logout = async () => {
+  const token = localStorage.getItem("token");
+  if (token) {
+    await fetch(`${API_BASE}/auth/logout`, {
+      method: "POST",
+      headers: { Authorization: `Bearer ${token}` },
+    }).catch(() => {});
+  }
+  localStorage.removeItem("token");
+  localStorage.removeItem("refresh_token");
+};
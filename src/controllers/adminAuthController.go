@@ -3,7 +3,9 @@ package controllers
 import (
 	"net/http"
 	"titik-rindang/src/database"
+	"titik-rindang/src/middlewares"
 	"titik-rindang/src/models"
+	"titik-rindang/src/services"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -50,6 +52,9 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
+	//user yang dihapus langsung logout dari semua device
+	_ = services.NewAuthService(database.DB).RevokeUserSessions(user.ID)
+
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully!"})
 }
 
@@ -75,6 +80,17 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	//role kosong = tetap, role baru harus sudah terdaftar
+	if input.Role == "" {
+		input.Role = user.Role
+	} else if !services.NewRoleService(database.DB).RoleExists(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role not found"})
+		return
+	}
+
+	//ganti password / role = semua sesi lama harus login ulang
+	revokeSessions := input.Password != "" || input.Role != user.Role
+
 	user.Username = input.Username
 	user.Email = input.Email
 	user.Role = input.Role
@@ -89,5 +105,53 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	if revokeSessions {
+		_ = services.NewAuthService(database.DB).RevokeUserSessions(user.ID)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
-}
\ No newline at end of file
+}
+
+//Suspend user: tidak bisa login dan token yang sudah ada langsung ditolak
+func SuspendUser(c *gin.Context) {
+	setUserStatus(c, models.UserSuspended, "User suspended")
+}
+
+//Aktifkan lagi user yang di-suspend / nonaktif (sekaligus buka kunci)
+func ReactivateUser(c *gin.Context) {
+	setUserStatus(c, models.UserActive, "User reactivated")
+}
+
+func setUserStatus(c *gin.Context, status, message string) {
+	id := c.Param("id")
+
+	var user models.Auth
+	if err := database.DB.First(&user, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	//admin tidak boleh mengunci dirinya sendiri
+	if status != models.UserActive && user.Username == middlewares.CurrentUsername(c) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot suspend your own account"})
+		return
+	}
+
+	if _, err := services.NewAuthService(database.DB).SetUserStatus(user.ID, status); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "id": user.ID, "status": status})
+}
+
+//Buka kunci akun yang terkunci karena salah password
+func UnlockUser(c *gin.Context) {
+	user, err := services.NewAuthService(database.DB).UnlockUser(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked", "id": user.ID})
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/models"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// Lihat audit log, filter: actor, action, entity, entity_id, from, to (YYYY-MM-DD atau RFC3339), page, limit
+func GetAuditLogs(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logs, total, err := services.NewAuditService(database.DB).GetLogs(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs":  logs,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.Limit,
+	})
+}
+
+// Export audit log ke CSV dengan filter yang sama (tanpa paging)
+func ExportAuditLogs(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := "audit-log-" + time.Now().Format("20060102-150405") + ".csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{
+		"id", "created_at", "actor", "actor_role", "action", "entity", "entity_id",
+		"method", "path", "status_code", "ip", "before", "after",
+	})
+
+	err = services.NewAuditService(database.DB).EachLog(filter, func(entry models.AuditLog) error {
+		return writer.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			entry.CreatedAt.Format(time.RFC3339),
+			entry.Actor,
+			entry.ActorRole,
+			entry.Action,
+			entry.Entity,
+			entry.EntityID,
+			entry.Method,
+			entry.Path,
+			strconv.Itoa(entry.StatusCode),
+			entry.IP,
+			string(entry.Before),
+			string(entry.After),
+		})
+	})
+	writer.Flush()
+	if err == nil {
+		err = writer.Error()
+	}
+	if err != nil {
+		// header sudah terkirim, hanya bisa dicatat
+		c.Error(err)
+	}
+}
+
+func parseAuditFilter(c *gin.Context) (services.AuditFilter, error) {
+	filter := services.AuditFilter{
+		Actor:    c.Query("actor"),
+		Action:   c.Query("action"),
+		Entity:   c.Query("entity"),
+		EntityID: c.Query("entity_id"),
+		Page:     1,
+		Limit:    defaultAuditLimit,
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, _, err := parseAuditTime(from)
+		if err != nil {
+			return filter, errors.New("Invalid from, use YYYY-MM-DD or RFC3339")
+		}
+		filter.From = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, dateOnly, err := parseAuditTime(to)
+		if err != nil {
+			return filter, errors.New("Invalid to, use YYYY-MM-DD or RFC3339")
+		}
+		// tanggal saja berarti sampai akhir hari tsb
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		filter.To = &t
+	}
+
+	if page := c.Query("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return filter, errors.New("Invalid page")
+		}
+		filter.Page = n
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxAuditLimit {
+			return filter, errors.New("Invalid limit, must be between 1 and " + strconv.Itoa(maxAuditLimit))
+		}
+		filter.Limit = n
+	}
+
+	return filter, nil
+}
+
+func parseAuditTime(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/events"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stream order/table/reservation events via Server-Sent Events
+// (optional ?topics=order,kitchen,reservation,table)
+func StreamEvents(c *gin.Context) {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+
+	sessionID := middlewares.CurrentSessionID(c)
+
+	filter := events.Filter{Permissions: services.NewRoleService(database.DB).PermissionsOf(roleStr)}
+	if topics := c.Query("topics"); topics != "" {
+		for _, topic := range strings.Split(topics, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				filter.Topics = append(filter.Topics, topic)
+			}
+		}
+	}
+
+	stream, unsubscribe := events.Subscribe(filter)
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// ping berkala supaya koneksi tidak diputus proxy
+	heartbeat := time.NewTicker(25 * time.Second)
+	defer heartbeat.Stop()
+
+	c.SSEvent("ready", gin.H{"role": roleStr, "topics": filter.Topics})
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-stream:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			// koneksi bisa hidup lama: sesi yang dicabut / permission yang berubah menutup stream,
+			// client login ulang atau reconnect dengan permission baru
+			if !services.NewAuthService(database.DB).SessionActive(sessionID) {
+				c.SSEvent("revoked", gin.H{"reason": "session has been revoked"})
+				return false
+			}
+			if !slices.Equal(services.NewRoleService(database.DB).PermissionsOf(roleStr), filter.Permissions) {
+				c.SSEvent("revoked", gin.H{"reason": "permissions changed"})
+				return false
+			}
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
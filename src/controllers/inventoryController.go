@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get all ingredients (?low=true untuk stok menipis)
+func GetAllIngredients(c *gin.Context) {
+	svc := services.NewInventoryService(database.DB)
+	ingredients, err := svc.GetAllIngredients(c.Query("low") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load ingredients"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   ingredients,
+	})
+}
+
+// Create ingredient
+func CreateIngredient(c *gin.Context) {
+	var input struct {
+		Name              string  `json:"name" binding:"required"`
+		Unit              string  `json:"unit" binding:"required"`
+		Stock             float64 `json:"stock"`
+		LowStockThreshold float64 `json:"low_stock_threshold"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	ingredient := models.Ingredient{
+		Name:              input.Name,
+		Unit:              input.Unit,
+		Stock:             input.Stock,
+		LowStockThreshold: input.LowStockThreshold,
+	}
+
+	svc := services.NewInventoryService(database.DB)
+	if err := svc.CreateIngredient(&ingredient, middlewares.CurrentUsername(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "ingredient created",
+		"data":    ingredient,
+	})
+}
+
+// Update ingredient
+func UpdateIngredient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid ingredient ID"})
+		return
+	}
+
+	var input struct {
+		Name              string  `json:"name"`
+		Unit              string  `json:"unit"`
+		LowStockThreshold float64 `json:"low_stock_threshold"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewInventoryService(database.DB)
+	ingredient, err := svc.UpdateIngredient(uint(id), &models.Ingredient{
+		Name:              input.Name,
+		Unit:              input.Unit,
+		LowStockThreshold: input.LowStockThreshold,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "ingredient updated",
+		"data":    ingredient,
+	})
+}
+
+// Delete ingredient
+func DeleteIngredient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid ingredient ID"})
+		return
+	}
+
+	svc := services.NewInventoryService(database.DB)
+	if err := svc.DeleteIngredient(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "ingredient deleted",
+	})
+}
+
+// Restock / waste / adjustment
+func AdjustIngredientStock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid ingredient ID"})
+		return
+	}
+
+	var input struct {
+		Change float64 `json:"change" binding:"required"`
+		Reason string  `json:"reason" binding:"required"`
+		Note   string  `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewInventoryService(database.DB)
+	ingredient, err := svc.AdjustStock(uint(id), input.Change, input.Reason, input.Note, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "stock updated",
+		"data":    ingredient,
+	})
+}
+
+// Stock movements of an ingredient
+func GetIngredientMovements(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid ingredient ID"})
+		return
+	}
+
+	svc := services.NewInventoryService(database.DB)
+	movements, err := svc.GetMovements(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   movements,
+	})
+}
+
+// Get recipe of a menu
+func GetMenuRecipe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("menu_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid menu ID"})
+		return
+	}
+
+	svc := services.NewInventoryService(database.DB)
+	recipe, err := svc.GetRecipe(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   recipe,
+	})
+}
+
+// Replace recipe of a menu
+func SetMenuRecipe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("menu_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid menu ID"})
+		return
+	}
+
+	var input struct {
+		Items []struct {
+			IngredientID uint    `json:"ingredient_id"`
+			Quantity     float64 `json:"quantity"`
+		} `json:"items"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	items := []models.RecipeItem{}
+	for _, item := range input.Items {
+		items = append(items, models.RecipeItem{IngredientID: item.IngredientID, Quantity: item.Quantity})
+	}
+
+	svc := services.NewInventoryService(database.DB)
+	recipe, err := svc.SetRecipe(uint(id), items)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "recipe updated",
+		"data":    recipe,
+	})
+}
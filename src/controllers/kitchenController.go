@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get kitchen queue (optional ?station=kitchen|bar)
+func GetKitchenQueue(c *gin.Context) {
+	station := c.Query("station")
+
+	svc := services.NewKitchenService(database.DB)
+	tickets, err := svc.GetQueue(station)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load kitchen queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   tickets,
+	})
+}
+
+// Update preparation status of an order item
+func UpdateOrderItemStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order item ID"})
+		return
+	}
+
+	var input struct {
+		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewKitchenService(database.DB)
+	item, err := svc.UpdateItemStatus(uint(id), input.Status, input.Reason, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "order item updated",
+		"data":    item,
+	})
+}
@@ -1,13 +1,18 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"titik-rindang/src/database"
+	"titik-rindang/src/helper"
 	"titik-rindang/src/middlewares"
 	"titik-rindang/src/models"
+	"titik-rindang/src/services"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 )
 
 //Login user
@@ -23,31 +28,89 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	//Search user by username
-	var user models.Auth
-	if err := database.DB.Where("username = ?", req.Username).First(&user).Error; err != nil {
+	//Check password, lockout & account status
+	svc := services.NewAuthService(database.DB)
+	user, err := svc.Authenticate(req.Username, req.Password)
+	switch {
+	case errors.Is(err, services.ErrAccountLocked):
+		minutes := int(math.Ceil(time.Until(*user.LockedUntil).Minutes()))
+		c.JSON(http.StatusLocked, gin.H{
+			"error":        fmt.Sprintf("Account locked after too many failed attempts, try again in %d minutes", minutes),
+			"locked_until": user.LockedUntil,
+		})
+		return
+	case errors.Is(err, services.ErrAccountInactive):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is " + user.Status + ", contact admin"})
+		return
+	case errors.Is(err, services.ErrInvalidCredentials):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
 		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+		return
 	}
 
-
-	//Password Check
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+	//Create session (refresh token)
+	session, refreshToken, err := svc.CreateSession(user, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
 	}
 
-	// //Generate JWT token
-	token, err := middlewares.GenerateToken(user.Username, user.Role)
+	//Generate JWT access token
+	token, err := middlewares.GenerateToken(user.Username, user.Role, session.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
 	//return token
-	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-		"role": user.Role,
-		"username": user.Username,
-		})	
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, tokenResponse(user, token, refreshToken))
+}
+
+//Tukar refresh token dengan pasangan token baru
+func RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	svc := services.NewAuthService(database.DB)
+	session, user, refreshToken, err := svc.RotateRefreshToken(req.RefreshToken, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	token, err := middlewares.GenerateToken(user.Username, user.Role, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResponse(user, token, refreshToken))
+}
+
+//Logout: cabut sesi milik token yang dipakai
+func Logout(c *gin.Context) {
+	svc := services.NewAuthService(database.DB)
+	if err := svc.RevokeSession(middlewares.CurrentSessionID(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
+}
+
+func tokenResponse(user *models.Auth, token, refreshToken string) gin.H {
+	return gin.H{
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(helper.GetAccessTokenTTL().Seconds()),
+		"role":          user.Role,
+		"permissions":   services.NewRoleService(database.DB).PermissionsOf(user.Role),
+		"username":      user.Username,
+	}
+}
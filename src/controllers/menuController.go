@@ -1,24 +1,39 @@
 package controllers
 
 import (
+	"errors"
+	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
-	"time"
 
 	"titik-rindang/src/database"
 	"titik-rindang/src/models"
+	"titik-rindang/src/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Get all menu
+// Get all menu (?grouped=true untuk dikelompokkan per kategori)
 func GetAllMenu(c *gin.Context) {
-	var menu []models.Menu
+	svc := services.NewMenuService(database.DB)
 
-	if err := database.DB.Find(&menu).Error; err != nil {
+	if c.Query("grouped") == "true" {
+		categories, err := svc.GetGroupedMenu()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load menu data"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "success",
+			"message": "menu data loaded successfully",
+			"data":    categories,
+		})
+		return
+	}
+
+	menu, err := svc.GetAllMenu()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load menu data"})
 		return
 	}
@@ -32,10 +47,14 @@ func GetAllMenu(c *gin.Context) {
 
 // Get menu by ID
 func GetMenuByID(c *gin.Context) {
-	id := c.Param("id")
-	var menu models.Menu
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid menu ID"})
+		return
+	}
 
-	if err := database.DB.First(&menu, id).Error; err != nil {
+	menu, err := services.NewMenuService(database.DB).GetMenuByID(uint(id))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "menu not found"})
 		return
 	}
@@ -52,48 +71,60 @@ func CreateMenu(c *gin.Context) {
 	name := c.PostForm("name")
 	tagline := c.PostForm("tagline")
 	priceStr := c.PostForm("price")
+	station := c.DefaultPostForm("station", "kitchen")
 
 	if name == "" || priceStr == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "name and price are required"})
 		return
 	}
 
-	price, err := strconv.ParseFloat(priceStr, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid price format"})
+	if station != "kitchen" && station != "bar" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "station must be kitchen or bar"})
 		return
 	}
 
-	file, err := c.FormFile("image")
+	price, err := models.ParseMoney(priceStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "image file is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid price format"})
 		return
 	}
 
-	uploadFolder := "src/uploads/menu"
-	if err := os.MkdirAll(uploadFolder, os.ModePerm); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to create upload folder"})
+	categoryID, err := parseCategoryID(c.PostForm("category_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid category_id"})
 		return
 	}
 
-	filename := time.Now().Format("20060102150405") + "_" + filepath.Base(file.Filename)
-	filePath := filepath.Join(uploadFolder, filename)
-
-	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to save image"})
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "image file is required"})
 		return
 	}
 
-	imageURL := "/uploads/menu/" + filename
-
 	menu := models.Menu{
 		Name:     name,
 		Tagline:  tagline,
-		ImageURL: imageURL,
 		Price: price,
+		Station:  station,
+		IsAvailable: c.DefaultPostForm("is_available", "true") != "false",
 	}
 
+	svc := services.NewMenuService(database.DB)
+	if err := svc.AssignCategory(&menu, categoryID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	images, err := saveMenuImage(svc, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	menu.Images = images
+	menu.ImageURL = images["full"]
+
 	if err := database.DB.Create(&menu).Error; err != nil {
+		svc.DeleteImages(images)
 		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to create menu"})
 		return
 	}
@@ -118,6 +149,7 @@ func UpdateMenu(c *gin.Context) {
     name := c.PostForm("name")
     tagline := c.PostForm("tagline")
     priceStr := c.PostForm("price")
+    station := c.PostForm("station")
 
     if name != "" {
         menu.Name = name
@@ -126,43 +158,62 @@ func UpdateMenu(c *gin.Context) {
         menu.Tagline = tagline
     }
     if priceStr != "" {
-        price, err := strconv.ParseFloat(priceStr, 64)
+        price, err := models.ParseMoney(priceStr)
         if err != nil {
             c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid price format"})
             return
         }
         menu.Price = price
     }
-
-    file, err := c.FormFile("image")
-    if err == nil {
-        if menu.ImageURL != "" {
-            oldPath := "." + menu.ImageURL // contoh: ./uploads/menu/xxxxx.jpg
-            _ = os.Remove(oldPath)
+    if station != "" {
+        if station != "kitchen" && station != "bar" {
+            c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "station must be kitchen or bar"})
+            return
         }
-
-        uploadFolder := "src/uploads/menu"
-        if err := os.MkdirAll(uploadFolder, os.ModePerm); err != nil {
-            c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to create upload folder"})
+        menu.Station = station
+    }
+    // category_id kosong = tidak diubah, "0" = lepas dari kategori
+    if categoryStr, ok := c.GetPostForm("category_id"); ok && categoryStr != "" {
+        categoryID, err := parseCategoryID(categoryStr)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid category_id"})
             return
         }
+        if !sameCategory(menu.CategoryID, categoryID) {
+            if err := services.NewMenuService(database.DB).AssignCategory(&menu, categoryID); err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+                return
+            }
+        }
+    }
 
-        filename := time.Now().Format("20060102150405") + "_" + filepath.Base(file.Filename)
-        filePath := filepath.Join(uploadFolder, filename)
+    svc := services.NewMenuService(database.DB)
+    oldImages := menu.Images
 
-        if err := c.SaveUploadedFile(file, filePath); err != nil {
-            c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to save image"})
+    file, err := c.FormFile("image")
+    if err == nil {
+        images, err := saveMenuImage(svc, file)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
             return
         }
-
-        menu.ImageURL = "/uploads/menu/" + filename
+        menu.Images = images
+        menu.ImageURL = images["full"]
     }
 
     if err := database.DB.Save(&menu).Error; err != nil {
+        if file != nil {
+            svc.DeleteImages(menu.Images)
+        }
         c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to update menu"})
         return
     }
 
+    // gambar lama baru dihapus setelah menu tersimpan
+    if file != nil {
+        svc.DeleteImages(oldImages)
+    }
+
     c.JSON(http.StatusOK, gin.H{
         "status":  "success",
         "message": "menu updated successfully",
@@ -172,32 +223,29 @@ func UpdateMenu(c *gin.Context) {
 
 // Delete menu
 func DeleteMenu(c *gin.Context) {
-	id := c.Param("id")
-	var menu models.Menu
-
-	if err := database.DB.First(&menu, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "menu not found",
-		})
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid menu ID"})
 		return
 	}
 
-	if menu.ImageURL != "" {
-		filePath := strings.TrimPrefix(menu.ImageURL, "/")
-
-		if _, err := os.Stat(filePath); err == nil {
-			if err := os.Remove(filePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": "failed to delete image file",
-				})
-				return
-			}
+	svc := services.NewMenuService(database.DB)
+	menu, err := svc.DeleteMenu(uint(id))
+	if err != nil {
+		if err.Error() == "menu not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "menu not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrMenuInUse) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
 		}
-	}
-
-	if err := database.DB.Delete(&menu).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
 			"message": "failed to delete menu",
@@ -205,8 +253,360 @@ func DeleteMenu(c *gin.Context) {
 		return
 	}
 
+	svc.DeleteImages(menu.Images)
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "menu deleted successfully",
 	})
-}
\ No newline at end of file
+}
+
+// "" / "0" berarti tanpa kategori
+func parseCategoryID(value string) (*uint, error) {
+	if value == "" || value == "0" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	categoryID := uint(id)
+	return &categoryID, nil
+}
+
+func sameCategory(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Urutkan ulang menu di dalam kategori
+func ReorderMenus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid category ID"})
+		return
+	}
+
+	var input struct {
+		MenuIDs []uint `json:"menu_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewMenuService(database.DB)
+	if err := svc.ReorderMenus(uint(id), input.MenuIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "menu order updated",
+	})
+}
+
+// Get all menu categories
+func GetAllMenuCategories(c *gin.Context) {
+	svc := services.NewMenuService(database.DB)
+	categories, err := svc.GetAllCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load menu categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   categories,
+	})
+}
+
+// Create menu category
+func CreateMenuCategory(c *gin.Context) {
+	var input struct {
+		Name     string `json:"name" binding:"required"`
+		Position int    `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	category := models.MenuCategory{Name: input.Name, Position: input.Position}
+
+	svc := services.NewMenuService(database.DB)
+	if err := svc.CreateCategory(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "menu category created",
+		"data":    category,
+	})
+}
+
+// Update menu category
+func UpdateMenuCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid category ID"})
+		return
+	}
+
+	var input struct {
+		Name     string `json:"name"`
+		Position int    `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewMenuService(database.DB)
+	category, err := svc.UpdateCategory(uint(id), input.Name, input.Position)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "menu category updated",
+		"data":    category,
+	})
+}
+
+// Urutkan ulang kategori
+func ReorderMenuCategories(c *gin.Context) {
+	var input struct {
+		CategoryIDs []uint `json:"category_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewMenuService(database.DB)
+	if err := svc.ReorderCategories(input.CategoryIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "menu category order updated",
+	})
+}
+
+// Delete menu category
+func DeleteMenuCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid category ID"})
+		return
+	}
+
+	svc := services.NewMenuService(database.DB)
+	if err := svc.DeleteCategory(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "menu category deleted",
+	})
+}
+
+type modifierGroupInput struct {
+	Name      string `json:"name" binding:"required"`
+	Required  bool   `json:"required"`
+	MinSelect int    `json:"min_select"`
+	MaxSelect *int   `json:"max_select"`
+	Options   []struct {
+		Name       string       `json:"name"`
+		PriceDelta models.Money `json:"price_delta"`
+	} `json:"options" binding:"required"`
+}
+
+func (input modifierGroupInput) toModel() models.ModifierGroup {
+	group := models.ModifierGroup{
+		Name:      input.Name,
+		Required:  input.Required,
+		MinSelect: input.MinSelect,
+		MaxSelect: 1,
+	}
+	if input.MaxSelect != nil {
+		group.MaxSelect = *input.MaxSelect
+	}
+	for _, option := range input.Options {
+		group.Options = append(group.Options, models.ModifierOption{Name: option.Name, PriceDelta: option.PriceDelta})
+	}
+	return group
+}
+
+// Get all modifier groups
+func GetAllModifierGroups(c *gin.Context) {
+	svc := services.NewMenuService(database.DB)
+	groups, err := svc.GetAllModifierGroups()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load modifier groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   groups,
+	})
+}
+
+// Create modifier group with its options
+func CreateModifierGroup(c *gin.Context) {
+	var input modifierGroupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	group := input.toModel()
+
+	svc := services.NewMenuService(database.DB)
+	if err := svc.CreateModifierGroup(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "modifier group created",
+		"data":    group,
+	})
+}
+
+// Update modifier group (options are replaced)
+func UpdateModifierGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid modifier group ID"})
+		return
+	}
+
+	var input modifierGroupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	updated := input.toModel()
+
+	svc := services.NewMenuService(database.DB)
+	group, err := svc.UpdateModifierGroup(uint(id), &updated)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "modifier group updated",
+		"data":    group,
+	})
+}
+
+// Delete modifier group
+func DeleteModifierGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid modifier group ID"})
+		return
+	}
+
+	svc := services.NewMenuService(database.DB)
+	if err := svc.DeleteModifierGroup(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "modifier group deleted",
+	})
+}
+
+// Attach modifier groups to a menu
+func SetMenuModifierGroups(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid menu ID"})
+		return
+	}
+
+	var input struct {
+		GroupIDs []uint `json:"group_ids"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewMenuService(database.DB)
+	menu, err := svc.SetMenuModifierGroups(uint(id), input.GroupIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "menu modifiers updated",
+		"data":    menu,
+	})
+}
+
+// Toggle sold out / jam tersedia menu
+func SetMenuAvailability(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid menu ID"})
+		return
+	}
+
+	var input struct {
+		IsAvailable    *bool   `json:"is_available"`
+		AvailableFrom  *string `json:"available_from"`
+		AvailableUntil *string `json:"available_until"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewMenuService(database.DB)
+	menu, err := svc.SetAvailability(uint(id), input.IsAvailable, input.AvailableFrom, input.AvailableUntil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "menu availability updated",
+		"data":    menu,
+	})
+}
+
+// Buka file upload lalu proses lewat pipeline gambar menu
+func saveMenuImage(svc *services.MenuService, file *multipart.FileHeader) (models.MenuImages, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, errors.New("failed to read image file")
+	}
+	defer src.Close()
+
+	return svc.SaveImage(src)
+}
@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"titik-rindang/src/database"
+	"titik-rindang/src/middlewares"
 	"titik-rindang/src/models"
 	"titik-rindang/src/services"
 
@@ -17,8 +19,9 @@ func CreateOrder(c *gin.Context) {
 		TableID  uint `json:"table_id" binding:"required"`
 		Customer string `json:"customer"`
 		Items []struct {
-			MenuID uint `json:"menu_id"`
-			Qty    int  `json:"qty"`
+			MenuID    uint   `json:"menu_id"`
+			Qty       int    `json:"qty"`
+			OptionIDs []uint `json:"option_ids"`
 		} `json:"items" binding:"required"`
 	}
 
@@ -37,12 +40,13 @@ func CreateOrder(c *gin.Context) {
 	orderItems := []services.OrderItemInput{}
 	for _, item := range input.Items {
 		orderItems = append(orderItems, services.OrderItemInput{
-			MenuID: item.MenuID,
-			Qty:    item.Qty,
+			MenuID:    item.MenuID,
+			Qty:       item.Qty,
+			OptionIDs: item.OptionIDs,
 		})
 	}
 
-	order, err := svc.CreateOrder(input.TableID, input.Customer, orderItems)
+	order, err := svc.CreateOrder(input.TableID, input.Customer, orderItems, middlewares.CurrentUsername(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
 		return
@@ -79,14 +83,16 @@ func GetAllOrders(c *gin.Context) {
 
 //Get orders by id
 func GetOrderByID(c *gin.Context) {
-	id := c.Param("id")
-
-	var order models.Order
-	if err := database.DB.
-		Preload("Table").
-		Preload("OrderItems.Menu").
-		First(&order, id).Error; err != nil {
+	idStr := c.Param("id")
+	idInt, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order ID"})
+		return
+	}
 
+	svc := services.NewOrderService(database.DB)
+	order, err := svc.GetOrder(uint(idInt))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"status": "error",
 			"message": "order not found",
@@ -100,6 +106,23 @@ func GetOrderByID(c *gin.Context) {
 	})
 }
 
+// track order by token (public)
+func TrackOrder(c *gin.Context) {
+	token := c.Param("token")
+
+	svc := services.NewOrderService(database.DB)
+	order, err := svc.GetOrderByToken(token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "order not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   order,
+	})
+}
+
 // confirm order
 func ConfirmOrder(c *gin.Context) {
 	idStr := c.Param("id")
@@ -119,7 +142,7 @@ func ConfirmOrder(c *gin.Context) {
 
 	svc := services.NewOrderService(database.DB)
 
-	order, err := svc.ConfirmOrder(uint(idInt), input.PaymentMethod)
+	order, err := svc.ConfirmOrder(uint(idInt), input.PaymentMethod, middlewares.CurrentUsername(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
 		return
@@ -132,9 +155,48 @@ func ConfirmOrder(c *gin.Context) {
 	})
 }
 
+// add payment (split bill / partial payment)
+func AddOrderPayment(c *gin.Context) {
+	idStr := c.Param("id")
+	idInt, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order ID"})
+		return
+	}
 
-//delete order
-func DeleteOrder(c *gin.Context) {
+	var input struct {
+		Method  string  `json:"method" binding:"required"`
+		Amount  models.Money `json:"amount"`
+		ItemIDs []uint  `json:"item_ids"`
+		Shares  int     `json:"shares"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewOrderService(database.DB)
+
+	order, err := svc.AddPayment(uint(idInt), services.PaymentInput{
+		Amount:  input.Amount,
+		Method:  input.Method,
+		ItemIDs: input.ItemIDs,
+		Shares:  input.Shares,
+	}, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "payment recorded",
+		"data":    order,
+	})
+}
+
+// add items to an unpaid order
+func AddOrderItems(c *gin.Context) {
 	idStr := c.Param("id")
 	idInt, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -142,23 +204,169 @@ func DeleteOrder(c *gin.Context) {
 		return
 	}
 
-	var order models.Order
-	if err := database.DB.First(&order, idInt).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "order not found"})
+	var input struct {
+		Items []struct {
+			MenuID    uint   `json:"menu_id"`
+			Qty       int    `json:"qty"`
+			OptionIDs []uint `json:"option_ids"`
+		} `json:"items" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
 		return
 	}
 
-	var table models.Table
-	if err := database.DB.First(&table, order.TableID).Error; err == nil {
-		table.Status = "available"
-		database.DB.Save(&table)
+	orderItems := []services.OrderItemInput{}
+	for _, item := range input.Items {
+		orderItems = append(orderItems, services.OrderItemInput{
+			MenuID:    item.MenuID,
+			Qty:       item.Qty,
+			OptionIDs: item.OptionIDs,
+		})
 	}
 
+	svc := services.NewOrderService(database.DB)
 
-	database.DB.Where("order_id = ?", idInt).Delete(&models.OrderItem{})
+	order, err := svc.AddItems(uint(idInt), orderItems, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
 
-	if err := database.DB.Delete(&models.Order{}, idInt).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to delete order"})
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "order items added",
+		"data":    order,
+	})
+}
+
+// change quantity of an order item
+func UpdateOrderItem(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order ID"})
+		return
+	}
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order item ID"})
+		return
+	}
+
+	var input struct {
+		Qty int `json:"qty" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewOrderService(database.DB)
+
+	order, err := svc.ChangeItemQuantity(uint(idInt), uint(itemID), input.Qty, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "order item updated",
+		"data":    order,
+	})
+}
+
+// void an order item (reason required)
+func VoidOrderItem(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order ID"})
+		return
+	}
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order item ID"})
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "void reason is required"})
+		return
+	}
+
+	svc := services.NewOrderService(database.DB)
+
+	order, err := svc.VoidItem(uint(idInt), uint(itemID), input.Reason, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "order item voided",
+		"data":    order,
+	})
+}
+
+// change order status (served, cancelled, refunded)
+func ChangeOrderStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	idInt, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order ID"})
+		return
+	}
+
+	var input struct {
+		Status string `json:"status" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewOrderService(database.DB)
+
+	order, err := svc.ChangeStatus(uint(idInt), input.Status, middlewares.CurrentUsername(c), input.Note)
+	if err != nil {
+		if errors.Is(err, services.ErrOrderItemsNotReady) || errors.Is(err, services.ErrOrderHasPayments) {
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "order status updated",
+		"data":    order,
+	})
+}
+
+//delete order
+func DeleteOrder(c *gin.Context) {
+	idStr := c.Param("id")
+	idInt, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid order ID"})
+		return
+	}
+
+	if err := services.NewOrderService(database.DB).DeleteOrder(uint(idInt)); err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotDeletable):
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+		case err.Error() == "order not found":
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "order not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to delete order"})
+		}
 		return
 	}
 
@@ -173,18 +381,14 @@ func PrintReceipt(c *gin.Context) {
 	idStr := c.Param("id")
 	id, _ := strconv.Atoi(idStr)
 
-	var order models.Order
-	if err := database.DB.
-		Preload("Table").
-		Preload("OrderItems.Menu").
-		First(&order, id).Error; err != nil {
-
+	order, err := services.NewOrderService(database.DB).GetOrder(uint(id))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
 		return
 	}
 
 	receiptSvc := services.NewReceiptService()
-	path, err := receiptSvc.GenerateReceipt(&order)
+	path, err := receiptSvc.GenerateReceipt(order)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate receipt"})
 		return
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"titik-rindang/src/database"
+	"titik-rindang/src/helper"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Admin mengizinkan / mencabut izin reset password user
+func SetResetAllowed(c *gin.Context) {
+	var input struct {
+		Allowed *bool `json:"allowed" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Field allowed is required"})
+		return
+	}
+
+	svc := services.NewAuthService(database.DB)
+	user, err := svc.SetResetAllowed(c.Param("id"), *input.Allowed)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reset permission"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Reset permission updated",
+		"id":            user.ID,
+		"reset_allowed": *input.Allowed,
+	})
+}
+
+// User minta link reset password lewat email
+func ForgotPassword(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	//response selalu sama supaya email terdaftar / tidak tidak bisa ditebak
+	message := "If the account is allowed to reset its password, a reset link has been sent to the email"
+
+	svc := services.NewAuthService(database.DB)
+	user, token, err := svc.RequestPasswordReset(strings.TrimSpace(input.Email))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, services.ErrResetNotAllowed) {
+			log.Printf("password reset request failed: %v", err)
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message})
+		return
+	}
+
+	if err := helper.SendPasswordResetEmail(user.Email, user.Username, token); err != nil {
+		log.Printf("failed to send password reset email to %s: %v", user.ID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// Set password baru pakai token dari email
+func ResetPassword(c *gin.Context) {
+	var input struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	//Password Validation
+	if valid, message := helper.ValidatePassword(input.Password); !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	svc := services.NewAuthService(database.DB)
+	if err := svc.ResetPassword(input.Token, input.Password); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidResetToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		case errors.Is(err, services.ErrResetNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Password reset is not allowed for this account"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset, please login again"})
+}
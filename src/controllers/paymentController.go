@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/payments"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type paymentInput struct {
+	Method string `json:"method" binding:"required,oneof=qris ewallet"`
+}
+
+// create payment for an order (customer, by tracking token)
+func CreateOrderPayment(c *gin.Context) {
+	var input paymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "method must be qris or ewallet"})
+		return
+	}
+
+	svc := services.NewPaymentService(database.DB)
+	intent, err := svc.CreateOrderIntent(c.Param("token"), input.Method)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "payment created",
+		"data":    intent,
+	})
+}
+
+// create payment for a reservation fee (customer, by payment token)
+func CreateReservationPayment(c *gin.Context) {
+	var input paymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "method must be qris or ewallet"})
+		return
+	}
+
+	svc := services.NewPaymentService(database.DB)
+	intent, err := svc.CreateReservationIntent(c.Param("token"), input.Method)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "payment created",
+		"data":    intent,
+	})
+}
+
+// get payment status
+func GetPaymentStatus(c *gin.Context) {
+	svc := services.NewPaymentService(database.DB)
+	intent, err := svc.GetIntent(c.Param("ref"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   intent,
+	})
+}
+
+// payments captured by the provider that could not be applied
+func GetUnreconciledPayments(c *gin.Context) {
+	svc := services.NewPaymentService(database.DB)
+	intents, err := svc.GetUnreconciled()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load payments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   intents,
+	})
+}
+
+// signed callback from payment provider
+func PaymentWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid body"})
+		return
+	}
+
+	svc := services.NewPaymentService(database.DB)
+	intent, err := svc.HandleWebhook(c.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if errors.Is(err, payments.ErrWebhookSecretMissing) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "message": "payment webhook is not configured"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   intent,
+	})
+}
+
+// simulate a successful payment (fake provider only)
+func SimulatePayment(c *gin.Context) {
+	svc := services.NewPaymentService(database.DB)
+	intent, err := svc.SimulatePayment(c.Param("ref"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "payment simulated",
+		"data":    intent,
+	})
+}
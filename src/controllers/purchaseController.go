@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type supplierInput struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Email   string `json:"email"`
+	Address string `json:"address"`
+	Notes   string `json:"notes"`
+}
+
+func (input supplierInput) toModel() models.Supplier {
+	return models.Supplier{
+		Name:    input.Name,
+		Phone:   input.Phone,
+		Email:   input.Email,
+		Address: input.Address,
+		Notes:   input.Notes,
+	}
+}
+
+// Get all suppliers
+func GetAllSuppliers(c *gin.Context) {
+	svc := services.NewPurchaseService(database.DB)
+	suppliers, err := svc.GetAllSuppliers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load suppliers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   suppliers,
+	})
+}
+
+// Create supplier
+func CreateSupplier(c *gin.Context) {
+	var input supplierInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	supplier := input.toModel()
+
+	svc := services.NewPurchaseService(database.DB)
+	if err := svc.CreateSupplier(&supplier); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "supplier created",
+		"data":    supplier,
+	})
+}
+
+// Update supplier
+func UpdateSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid supplier ID"})
+		return
+	}
+
+	var input supplierInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	updated := input.toModel()
+
+	svc := services.NewPurchaseService(database.DB)
+	supplier, err := svc.UpdateSupplier(uint(id), &updated)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "supplier updated",
+		"data":    supplier,
+	})
+}
+
+// Delete supplier
+func DeleteSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid supplier ID"})
+		return
+	}
+
+	svc := services.NewPurchaseService(database.DB)
+	if err := svc.DeleteSupplier(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "supplier deleted",
+	})
+}
+
+type purchaseOrderInput struct {
+	SupplierID uint       `json:"supplier_id"`
+	Notes      string     `json:"notes"`
+	ExpectedAt *time.Time `json:"expected_at"`
+	Items      []struct {
+		IngredientID uint         `json:"ingredient_id"`
+		Quantity     float64      `json:"quantity"`
+		UnitCost     models.Money `json:"unit_cost"`
+	} `json:"items"`
+}
+
+func (input purchaseOrderInput) toModel() models.PurchaseOrder {
+	po := models.PurchaseOrder{
+		SupplierID: input.SupplierID,
+		Notes:      input.Notes,
+		ExpectedAt: input.ExpectedAt,
+	}
+	if input.Items != nil {
+		po.Items = []models.PurchaseOrderItem{}
+	}
+	for _, item := range input.Items {
+		po.Items = append(po.Items, models.PurchaseOrderItem{
+			IngredientID: item.IngredientID,
+			Quantity:     item.Quantity,
+			UnitCost:     item.UnitCost,
+		})
+	}
+	return po
+}
+
+// Get all purchase orders (?status=)
+func GetAllPurchaseOrders(c *gin.Context) {
+	svc := services.NewPurchaseService(database.DB)
+	orders, err := svc.GetAllPurchaseOrders(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load purchase orders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   orders,
+	})
+}
+
+// Get purchase order by ID
+func GetPurchaseOrderByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid purchase order ID"})
+		return
+	}
+
+	svc := services.NewPurchaseService(database.DB)
+	po, err := svc.GetPurchaseOrder(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   po,
+	})
+}
+
+// Create purchase order (draft)
+func CreatePurchaseOrder(c *gin.Context) {
+	var input purchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	po := input.toModel()
+
+	svc := services.NewPurchaseService(database.DB)
+	created, err := svc.CreatePurchaseOrder(&po, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "purchase order created",
+		"data":    created,
+	})
+}
+
+// Update draft purchase order
+func UpdatePurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid purchase order ID"})
+		return
+	}
+
+	var input purchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	updated := input.toModel()
+
+	svc := services.NewPurchaseService(database.DB)
+	po, err := svc.UpdatePurchaseOrder(uint(id), &updated)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "purchase order updated",
+		"data":    po,
+	})
+}
+
+// Change purchase order status (ordered, cancelled)
+func ChangePurchaseOrderStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid purchase order ID"})
+		return
+	}
+
+	var input struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	svc := services.NewPurchaseService(database.DB)
+	po, err := svc.ChangeStatus(uint(id), input.Status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "purchase order status updated",
+		"data":    po,
+	})
+}
+
+// Receive goods for a purchase order
+func ReceivePurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid purchase order ID"})
+		return
+	}
+
+	var input struct {
+		Note  string `json:"note"`
+		Items []struct {
+			ItemID   uint          `json:"item_id"`
+			Quantity float64       `json:"quantity"`
+			UnitCost *models.Money `json:"unit_cost"`
+		} `json:"items" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid JSON"})
+		return
+	}
+
+	items := []services.ReceiveItemInput{}
+	for _, item := range input.Items {
+		items = append(items, services.ReceiveItemInput{
+			PurchaseOrderItemID: item.ItemID,
+			Quantity:            item.Quantity,
+			UnitCost:            item.UnitCost,
+		})
+	}
+
+	svc := services.NewPurchaseService(database.DB)
+	po, err := svc.ReceiveGoods(uint(id), items, input.Note, middlewares.CurrentUsername(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "goods received",
+		"data":    po,
+	})
+}
+
+// Generate purchase order PDF and return its URL
+func PrintPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid purchase order ID"})
+		return
+	}
+
+	svc := services.NewPurchaseService(database.DB)
+	po, err := svc.GetPurchaseOrder(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	url, err := svc.GeneratePDF(po)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to generate purchase order PDF"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"pdf":    url,
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"titik-rindang/src/database"
 	"titik-rindang/src/helper"
 	"titik-rindang/src/models"
+	"titik-rindang/src/services"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -37,6 +38,12 @@ func Register(c *gin.Context) {
 		input.Role = "staff"
 	}
 
+	//Role harus sudah terdaftar (lihat /admin/roles)
+	if !services.NewRoleService(database.DB).RoleExists(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role not found"})
+		return
+	}
+
 	//Check user has been used or not
 	var existingUser models.Auth
 	if err := database.DB.Unscoped().Where("email = ? OR username = ?", input.Email, input.Username).First(&existingUser).Error;
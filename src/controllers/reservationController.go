@@ -12,7 +12,6 @@ import (
 	"titik-rindang/src/services"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 // Create Reservation
@@ -22,7 +21,9 @@ func CreateReservation(c *gin.Context) {
 		Phone           string `json:"phone" binding:"required"`
 		Email           string `json:"email" binding:"required,email"`
 		TableID         uint   `json:"table_id" binding:"required"`
+		PartySize       int    `json:"party_size" binding:"required,min=1"`
 		ReservationDate string `json:"reservation_date" binding:"required"`
+		Duration        int    `json:"duration"` // menit, optional
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -41,13 +42,26 @@ func CreateReservation(c *gin.Context) {
 		Phone:           input.Phone,
 		Email:           input.Email,
 		TableID:         input.TableID,
+		PartySize:       input.PartySize,
 		ReservationDate: resDate,
+		Duration:        input.Duration,
 		Status: "Unpaid",
 	}
 
 	svc := services.NewReservationService(database.DB)
 	if err := svc.CreateReservation(&reservation); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Table not available, failed to create reservations"})
+		// kasih alternatif meja yang muat & kosong di jam yang sama
+		suggestions := []models.Table{}
+		if !reservation.EndDate.IsZero() {
+			availabilitySvc := services.NewAvailabilityService(database.DB)
+			suggestions, _ = availabilitySvc.SuggestTables(reservation.PartySize, reservation.ReservationDate, reservation.EndDate)
+		}
+
+		c.JSON(http.StatusConflict, gin.H{
+			"status":      "error",
+			"message":     "failed to create reservation: " + err.Error(),
+			"suggestions": suggestions,
+		})
 		return
 	}
 
@@ -80,51 +94,23 @@ func ConfirmReservation(c *gin.Context) {
 		return
 	}
 
-	// Update status reservation to "paid"
-	reservation.Status = "Paid"
-	if err := database.DB.Save(reservation).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	invoice, err := svc.ConfirmReservation(reservation)
+	if errors.Is(err, services.ErrReservationNotUnpaid) {
+		c.JSON(http.StatusConflict, gin.H{
 			"status":  "error",
-			"message": "failed to confirm reservation",
+			"message": err.Error(),
 		})
 		return
 	}
-
-	var invoice models.Invoice
-	err = database.DB.Where("reservation_id = ?", reservation.ID).First(&invoice).Error
-
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			invoiceSvc := services.NewInvoiceService(database.DB)
-			newInvoice, err := invoiceSvc.CreateInvoice(reservation)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": "failed to create invoice",
-				})
-				return
-			}
-			invoice = *newInvoice
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": "failed to fetch invoice data",
-			})
-			return
-		}
-	}
-
-	invoice.PaymentMethod = "Paid"
-	if err := database.DB.Save(&invoice).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
-			"message": "failed to update invoice status",
+			"message": "failed to confirm reservation",
 		})
 		return
 	}
 
-
-	if err := helper.SendInvoiceEmail(reservation.Email, &invoice); err != nil {
+	if err := helper.SendInvoiceEmail(reservation.Email, invoice); err != nil {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "success",
 			"message": "reservation confirmed, but failed to send invoice email",
@@ -195,8 +181,11 @@ func UpdateReservation(c *gin.Context) {
 	}
 
 	var input struct {
-		Status  string `json:"status"`
-		TableID *uint  `json:"table_id"`
+		Status          string `json:"status"`
+		TableID         *uint  `json:"table_id"`
+		PartySize       int    `json:"party_size"`
+		ReservationDate string `json:"reservation_date"`
+		Duration        int    `json:"duration"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -211,11 +200,21 @@ func UpdateReservation(c *gin.Context) {
 	if input.TableID != nil {
 		updatedData.TableID = *input.TableID
 	}
+	if input.ReservationDate != "" {
+		resDate, err := time.Parse(time.RFC3339, input.ReservationDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid reservation date format"})
+			return
+		}
+		updatedData.ReservationDate = resDate
+	}
+	updatedData.Duration = input.Duration
+	updatedData.PartySize = input.PartySize
 
 	svc := services.NewReservationService(database.DB)
 	updatedReservation, err := svc.UpdateReservation(uint(id), updatedData)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to update reservation"})
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to update reservation: " + err.Error()})
 		return
 	}
 
@@ -258,4 +257,48 @@ func GetReservationFee(c *gin.Context) {
             "reservation_fee": fee,
         },
     })
-}
\ No newline at end of file
+}
+
+// Get table availability per time slot
+func GetReservationAvailability(c *gin.Context) {
+	dateStr := c.Query("date")
+	if dateStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "date is required (YYYY-MM-DD)"})
+		return
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid date format, use YYYY-MM-DD"})
+		return
+	}
+
+	partySize := 0
+	if sizeStr := c.Query("party_size"); sizeStr != "" {
+		partySize, err = strconv.Atoi(sizeStr)
+		if err != nil || partySize <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "invalid party size"})
+			return
+		}
+	}
+
+	zone := c.Query("zone")
+
+	svc := services.NewAvailabilityService(database.DB)
+	slots, err := svc.GetAvailability(date, partySize, zone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "failed to load availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "availability loaded successfully",
+		"data": gin.H{
+			"date":       dateStr,
+			"party_size": partySize,
+			"zone":       zone,
+			"slots":      slots,
+		},
+	})
+}
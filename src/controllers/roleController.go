@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"titik-rindang/src/database"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetAllPermissions(c *gin.Context) {
+	permissions, err := services.NewRoleService(database.DB).GetAllPermissions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch permissions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"permissions": permissions})
+}
+
+func GetAllRoles(c *gin.Context) {
+	roles, err := services.NewRoleService(database.DB).GetAllRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch roles"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// Buat role custom, contoh: {"name": "barista", "permissions": ["kitchen.view", "kitchen.update"]}
+func CreateRole(c *gin.Context) {
+	var input services.RoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	role, err := services.NewRoleService(database.DB).CreateRole(input)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Role created successfully", "role": role})
+}
+
+// Ganti deskripsi & daftar permission role (nama role tidak bisa diubah)
+func UpdateRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role id"})
+		return
+	}
+
+	var input services.RoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	role, err := services.NewRoleService(database.DB).UpdateRole(uint(id), input)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated successfully", "role": role})
+}
+
+func DeleteRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role id"})
+		return
+	}
+
+	if err := services.NewRoleService(database.DB).DeleteRole(uint(id)); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+}
+
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRoleExists), errors.Is(err, services.ErrRoleInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSystemRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRoleName), errors.Is(err, services.ErrUnknownPermission):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save role"})
+	}
+}
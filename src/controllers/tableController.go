@@ -13,8 +13,12 @@ import (
 // Create Table
 func CreateTable(c *gin.Context) {
 	var input struct {
-		TableNo int    `json:"table_no" binding:"required"`
-		Status  string `json:"status"`
+		TableNo     int      `json:"table_no" binding:"required"`
+		Status      string   `json:"status"`
+		MinCapacity int      `json:"min_capacity"`
+		MaxCapacity int      `json:"max_capacity"`
+		Zone        string   `json:"zone"`
+		Features    []string `json:"features"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -25,14 +29,19 @@ func CreateTable(c *gin.Context) {
 	}
 
 	table := models.Table{
-		TableNo: input.TableNo,
-		Status:  input.Status,
+		TableNo:     input.TableNo,
+		Status:      input.Status,
+		MinCapacity: input.MinCapacity,
+		MaxCapacity: input.MaxCapacity,
+		Zone:        input.Zone,
+		Features:    input.Features,
 	}
 
 	svc := services.NewTableService(database.DB)
 	if err := svc.CreateTable(&table); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "Failed to create table.",
+			"error":   err.Error(),
 		})
 		return
 	}
@@ -98,8 +107,12 @@ func UpdateTable(c *gin.Context) {
 	}
 
 	var input struct {
-		TableNo int    `json:"table_no"`
-		Status  string `json:"status"`
+		TableNo     int      `json:"table_no"`
+		Status      string   `json:"status"`
+		MinCapacity int      `json:"min_capacity"`
+		MaxCapacity int      `json:"max_capacity"`
+		Zone        string   `json:"zone"`
+		Features    []string `json:"features"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -110,8 +123,12 @@ func UpdateTable(c *gin.Context) {
 	}
 
 	table := &models.Table{
-		TableNo: input.TableNo,
-		Status:  input.Status,
+		TableNo:     input.TableNo,
+		Status:      input.Status,
+		MinCapacity: input.MinCapacity,
+		MaxCapacity: input.MaxCapacity,
+		Zone:        input.Zone,
+		Features:    input.Features,
 	}
 
 	svc := services.NewTableService(database.DB)
@@ -119,6 +136,7 @@ func UpdateTable(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "Failed to update table.",
+			"error":   err.Error(),
 		})
 		return
 	}
@@ -2,7 +2,7 @@ package database
 
 import (
 	"fmt"
-
+	"log"
 	"os"
 
 	"titik-rindang/src/models"
@@ -27,16 +27,130 @@ func ConnectDB() (*gorm.DB, error) {
 		return nil, err
 	}
 
-	db.AutoMigrate(
+	if err := Migrate(db); err != nil {
+		log.Printf("database migration failed: %v", err)
+		return nil, err
+	}
+
+	DB = db
+	fmt.Println("Database connected successfully!")
+
+	return  db, nil
+}
+
+// Buat / update skema beserta perbaikan data lama, juga dipakai test dengan TEST_DATABASE_URL
+func Migrate(db *gorm.DB) error {
+	if err := convertMoneyColumns(db); err != nil {
+		return fmt.Errorf("convert money columns: %w", err)
+	}
+
+	if err := db.AutoMigrate(
+		models.AuditLog{},
 		&models.Auth{}, 
+		models.Ingredient{},
+		&models.GoodsReceipt{},
+		&models.GoodsReceiptItem{},
 		models.Invoice{}, 
 		models.Menu{},
+		models.MenuCategory{},
+		models.ModifierGroup{},
+		models.ModifierOption{},
+		models.Order{},
 		models.OrderItem{},
+		models.OrderStatusHistory{},
+		models.OrderItemChange{},
+		models.PasswordResetToken{},
+		models.PaymentIntent{},
+		models.Payment{},
+		models.PaymentItem{},
+		models.Permission{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderItem{},
+		models.RecipeItem{},
 		models.Reservation{},
-		models.Table{})
+		models.Role{},
+		models.Session{},
+		models.StockMovement{},
+		&models.Supplier{},
+		models.Table{}); err != nil {
+		return fmt.Errorf("auto migrate: %w", err)
+	}
 
-	DB = db
-	fmt.Println("Database connected successfully!")
+	for _, step := range migrationSteps {
+		if err := db.Exec(step.sql).Error; err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+	return nil
+}
 
-	return  db, nil
-}
\ No newline at end of file
+// Perbaikan data lama & trigger, dijalankan setiap start jadi harus aman diulang
+var migrationSteps = []struct {
+	name string
+	sql  string
+}{
+	// reservasi lama belum punya jam selesai
+	{"backfill reservation end date", "UPDATE reservations SET end_date = reservation_date + duration * INTERVAL '1 minute' WHERE end_date IS NULL"},
+
+	// reservasi lama belum punya payment token
+	{"backfill reservation payment token", "UPDATE reservations SET payment_token = REPLACE(gen_random_uuid()::text, '-', '') WHERE payment_token IS NULL"},
+
+	// status order lama: unpaid -> open
+	{"rename unpaid orders to open", "UPDATE orders SET status = 'open' WHERE status = 'unpaid'"},
+
+	// order lama belum punya tracking token
+	{"backfill order tracking token", "UPDATE orders SET tracking_token = REPLACE(gen_random_uuid()::text, '-', '') WHERE tracking_token IS NULL"},
+
+	// order lama belum punya rincian pajak
+	{"backfill order subtotal", "UPDATE orders SET subtotal = total WHERE subtotal = 0 AND total > 0"},
+
+	// dulu pembulatan tunai sudah masuk Total, sekarang hanya dicatat saat pelunasan tunai
+	{"remove cash rounding from order total", "UPDATE orders SET total = total - rounding WHERE rounding <> 0 AND total = subtotal + service_charge + tax + rounding"},
+	{"reset rounding of unpaid orders", "UPDATE orders SET rounding = 0 WHERE rounding <> 0 AND status IN ('open', 'served')"},
+
+	// item dari sebelum ada layar dapur jangan masuk antrian
+	{"mark old order items served", "UPDATE order_items SET prep_status = 'served', created_at = NOW() WHERE created_at IS NULL"},
+
+	// item lama belum punya snapshot nama & harga
+	{"backfill order item snapshots", "UPDATE order_items SET menu_name = menus.name, unit_price = order_items.subtotal / GREATEST(order_items.quantity, 1) FROM menus WHERE menus.id = order_items.menu_id AND (order_items.menu_name IS NULL OR order_items.menu_name = '')"},
+
+	// user lama tanpa status dianggap aktif
+	{"backfill user status", "UPDATE auths SET status = 'active' WHERE status IS NULL OR status = ''"},
+
+	// audit log append-only, juga untuk query manual di luar aplikasi
+	{"create audit log trigger function", `CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+		BEGIN RAISE EXCEPTION 'audit_logs is append-only'; END;
+		$$ LANGUAGE plpgsql`},
+	{"drop audit log trigger", "DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs"},
+	{"create audit log trigger", "CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only()"},
+}
+
+// Kolom uang yang dulu float -> bigint (rupiah penuh)
+var moneyColumns = map[string][]string{
+	"menus":           {"price"},
+	"orders":          {"subtotal", "service_charge", "tax", "rounding", "total"},
+	"order_items":     {"subtotal"},
+	"reservations":    {"table_fee"},
+	"invoices":        {"amount_paid"},
+	"payment_intents": {"amount"},
+	"payments":        {"amount"},
+	"payment_items":   {"amount"},
+}
+
+func convertMoneyColumns(db *gorm.DB) error {
+	for table, columns := range moneyColumns {
+		for _, column := range columns {
+			var dataType string
+			if err := db.Raw("SELECT data_type FROM information_schema.columns WHERE table_name = ? AND column_name = ?", table, column).Scan(&dataType).Error; err != nil {
+				return err
+			}
+			if dataType != "double precision" && dataType != "numeric" && dataType != "real" {
+				continue
+			}
+			if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s)::bigint", table, column, column)).Error; err != nil {
+				return fmt.Errorf("%s.%s: %w", table, column, err)
+			}
+		}
+	}
+	return nil
+}
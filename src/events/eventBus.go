@@ -0,0 +1,101 @@
+package events
+
+import (
+	"time"
+
+	"titik-rindang/src/models"
+)
+
+// Topic event
+const (
+	TopicOrder       = "order"
+	TopicKitchen     = "kitchen"
+	TopicReservation = "reservation"
+	TopicTable       = "table"
+	TopicInventory   = "inventory"
+)
+
+// Tipe event
+const (
+	OrderCreated         = "order.created"
+	OrderPaid            = "order.paid"
+	OrderPaymentAdded    = "order.payment_added"
+	OrderStatusChanged   = "order.status_changed"
+	OrderItemStatus      = "order.item_status"
+	OrderItemsChanged    = "order.items_changed"
+	ReservationCreated   = "reservation.created"
+	ReservationConfirmed = "reservation.confirmed"
+	TableStatusChanged   = "table.status_changed"
+	InventoryLowStock    = "inventory.low_stock"
+	MenuSoldOut          = "menu.sold_out"
+)
+
+// Permission yang dibutuhkan untuk menerima tiap topic
+var topicPermissions = map[string]string{
+	TopicOrder:       models.PermOrderView,
+	TopicKitchen:     models.PermKitchenView,
+	TopicReservation: models.PermReservationView,
+	TopicTable:       models.PermOrderView,
+	TopicInventory:   models.PermInventoryView,
+}
+
+type Event struct {
+	Topic string      `json:"topic"`
+	Type  string      `json:"type"`
+	Data  interface{} `json:"data"`
+	At    time.Time   `json:"at"`
+}
+
+// Filter subscriber, Permissions = permission role subscriber, Topics kosong berarti semua topic
+type Filter struct {
+	Permissions []string
+	Topics      []string
+}
+
+func (f Filter) Match(event Event) bool {
+	allowed := false
+	for _, permission := range f.Permissions {
+		if permission == topicPermissions[event.Topic] {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return false
+	}
+
+	if len(f.Topics) == 0 {
+		return true
+	}
+	for _, topic := range f.Topics {
+		if topic == event.Topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Bus bisa diganti implementasi lain (Redis, NATS, dll) lewat SetBus
+type Bus interface {
+	Publish(event Event)
+	Subscribe(filter Filter) (<-chan Event, func())
+}
+
+var bus Bus = NewMemoryBus()
+
+func SetBus(b Bus) {
+	bus = b
+}
+
+func Subscribe(filter Filter) (<-chan Event, func()) {
+	return bus.Subscribe(filter)
+}
+
+func Publish(topic, eventType string, data interface{}) {
+	bus.Publish(Event{
+		Topic: topic,
+		Type:  eventType,
+		Data:  data,
+		At:    time.Now(),
+	})
+}
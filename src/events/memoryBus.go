@@ -0,0 +1,58 @@
+package events
+
+import "sync"
+
+type subscriber struct {
+	ch     chan Event
+	filter Filter
+}
+
+// Bus in-process, cukup untuk satu instance backend
+type MemoryBus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]*subscriber
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{subs: map[int]*subscriber{}}
+}
+
+func (b *MemoryBus) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subs {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		// subscriber yang lambat di-skip, jangan sampai nahan request
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+func (b *MemoryBus) Subscribe(filter Filter) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	sub := &subscriber{ch: make(chan Event, 32), filter: filter}
+	b.subs[id] = sub
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+
+	return sub.ch, unsubscribe
+}
@@ -0,0 +1,102 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Ukuran hasil resize, gambar dimasukkan ke kotak Size x Size tanpa diperbesar
+type ImageVariant struct {
+	Name string
+	Size int
+}
+
+var ImageVariants = []ImageVariant{
+	{Name: "thumb", Size: 160},
+	{Name: "card", Size: 480},
+	{Name: "full", Size: 1200},
+}
+
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// Batas resolusi supaya file kecil beresolusi raksasa tidak menghabiskan memori
+const maxImagePixels = 40_000_000
+
+// MAX_IMAGE_SIZE_MB (default 5)
+func GetMaxImageSize() int64 {
+	return int64(getEnvInt("MAX_IMAGE_SIZE_MB", 5)) << 20
+}
+
+// Validasi isi file (bukan nama / header dari client), lalu re-encode ke JPEG
+// untuk setiap varian. Metadata EXIF ikut terbuang.
+func ProcessImage(r io.Reader) (map[string][]byte, error) {
+	limit := GetMaxImageSize()
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("image must not exceed %d MB", limit>>20)
+	}
+
+	if !allowedImageTypes[http.DetectContentType(data)] {
+		return nil, errors.New("image must be a JPEG, PNG or WebP file")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("image file is corrupted")
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.New("image resolution is too large")
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("image file is corrupted")
+	}
+
+	variants := map[string][]byte{}
+	for _, variant := range ImageVariants {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resizeToFit(src, variant.Size), &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		variants[variant.Name] = buf.Bytes()
+	}
+	return variants, nil
+}
+
+// Perkecil proporsional ke dalam kotak size x size, background putih untuk PNG transparan
+func resizeToFit(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			height = max(1, height*size/width)
+			width = size
+		} else {
+			width = max(1, width*size/height)
+			height = size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
@@ -4,10 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
-	"os"
 	"titik-rindang/src/models"
-
-	"gopkg.in/gomail.v2"
 )
 
 
@@ -23,25 +20,10 @@ func SendInvoiceEmail(to string, invoice *models.Invoice) error {
 		Invoice: invoice,
 	}
 
-	body := ""
 	buf := &bytes.Buffer{}
 	if err := tmpl.Execute(buf, data); err != nil {
 		return err
 	}
-	body = buf.String()
-
-	m := gomail.NewMessage()
-	m.SetHeader("From", os.Getenv("SMTP_EMAIL"))
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", fmt.Sprintf("Invoice #%s - Titik Rindang", invoice.InvoiceNumber))
-	m.SetBody("text/html", body)
-
-	d := gomail.NewDialer(
-		os.Getenv("SMTP_HOST"),
-		587,
-		os.Getenv("SMTP_EMAIL"),
-		os.Getenv("SMTP_PASSWORD"),
-	)
 
-	return d.DialAndSend(m)
-}
\ No newline at end of file
+	return sendHTMLEmail(to, fmt.Sprintf("Invoice #%s - Titik Rindang", invoice.InvoiceNumber), buf.String())
+}
@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"os"
+
+	"gopkg.in/gomail.v2"
+)
+
+// Kirim email HTML lewat SMTP (SMTP_HOST, SMTP_EMAIL, SMTP_PASSWORD)
+func sendHTMLEmail(to, subject, body string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", os.Getenv("SMTP_EMAIL"))
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", body)
+
+	d := gomail.NewDialer(
+		os.Getenv("SMTP_HOST"),
+		587,
+		os.Getenv("SMTP_EMAIL"),
+		os.Getenv("SMTP_PASSWORD"),
+	)
+
+	return d.DialAndSend(m)
+}
+
+// Kirim link reset password, token hanya ada di email ini
+func SendPasswordResetEmail(to, username, token string) error {
+	tmpl, err := template.ParseFiles("src/templates/passwordResetEmail.gohtml")
+	if err != nil {
+		return err
+	}
+
+	baseURL := os.Getenv("PASSWORD_RESET_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:3000/reset-password"
+	}
+
+	data := struct {
+		Username  string
+		Link      string
+		ExpiresIn int
+	}{
+		Username:  username,
+		Link:      baseURL + "?token=" + url.QueryEscape(token),
+		ExpiresIn: int(GetPasswordResetTTL().Minutes()),
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return err
+	}
+
+	return sendHTMLEmail(to, "Reset Password - Titik Rindang", buf.String())
+}
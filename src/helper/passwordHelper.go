@@ -3,6 +3,7 @@ package helper
 import (
 	"fmt"
 	"regexp"
+	"time"
 )
 
 //Validate password to check if the password meets the criteria or no
@@ -29,4 +30,14 @@ func ValidatePassword(password string) (bool, string) {
 	}
 
 	return  true, ""
-}
\ No newline at end of file
+}
+
+// Batas salah password sebelum akun dikunci (MAX_LOGIN_ATTEMPTS)
+func GetMaxLoginAttempts() int {
+	return getEnvInt("MAX_LOGIN_ATTEMPTS", 5)
+}
+
+// Lama akun dikunci setelah terlalu sering salah password (LOGIN_LOCKOUT_MINUTES)
+func GetLoginLockout() time.Duration {
+	return time.Duration(getEnvInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute
+}
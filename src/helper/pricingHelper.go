@@ -0,0 +1,77 @@
+package helper
+
+import (
+	"os"
+	"strconv"
+
+	"titik-rindang/src/models"
+)
+
+type PricingConfig struct {
+	ServiceRate  float64      // persen service charge
+	TaxRate      float64      // persen PB1
+	RoundingUnit models.Money // pembulatan tunai: 100 atau 500, dipakai saat bayar tunai
+}
+
+// Rincian tagihan order
+type OrderCharges struct {
+	Subtotal      models.Money
+	ServiceRate   float64
+	ServiceCharge models.Money
+	TaxRate       float64
+	Tax           models.Money
+	Total         models.Money
+}
+
+// SERVICE_CHARGE_PERCENT (default 0), PB1_TAX_PERCENT (default 10), CASH_ROUNDING (100/500, default 100)
+func GetPricingConfig() PricingConfig {
+	rounding := models.Money(getEnvInt("CASH_ROUNDING", 100))
+	if rounding != 100 && rounding != 500 {
+		rounding = 100
+	}
+
+	return PricingConfig{
+		ServiceRate:  getEnvFloat("SERVICE_CHARGE_PERCENT", 0),
+		TaxRate:      getEnvFloat("PB1_TAX_PERCENT", 10),
+		RoundingUnit: rounding,
+	}
+}
+
+func CalculateOrderCharges(subtotal models.Money) OrderCharges {
+	return ApplyCharges(subtotal, GetPricingConfig())
+}
+
+// Urutan: service charge dari subtotal, lalu PB1 dari (subtotal + service).
+// Total tidak dibulatkan, pembulatan hanya untuk pembayaran tunai (lihat CashDue).
+func ApplyCharges(subtotal models.Money, cfg PricingConfig) OrderCharges {
+	service := subtotal.Percent(cfg.ServiceRate)
+	tax := (subtotal + service).Percent(cfg.TaxRate)
+
+	return OrderCharges{
+		Subtotal:      subtotal,
+		ServiceRate:   cfg.ServiceRate,
+		ServiceCharge: service,
+		TaxRate:       cfg.TaxRate,
+		Tax:           tax,
+		Total:         subtotal + service + tax,
+	}
+}
+
+// Nominal tunai untuk melunasi sisa tagihan, dibulatkan ke kelipatan unit terdekat.
+// Sisa yang kurang dari setengah unit dibayar pas, tidak dibulatkan jadi 0.
+func CashDue(remaining models.Money, unit models.Money) models.Money {
+	due := remaining.RoundTo(unit)
+	if due <= 0 {
+		return remaining
+	}
+	return due
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || value < 0 {
+		return fallback
+	}
+
+	return value
+}
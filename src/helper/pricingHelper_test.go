@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"testing"
+
+	"titik-rindang/src/models"
+)
+
+func TestApplyCharges(t *testing.T) {
+	tests := []struct {
+		name     string
+		subtotal models.Money
+		cfg      PricingConfig
+		want     OrderCharges
+	}{
+		{
+			name:     "service then PB1",
+			subtotal: 100000,
+			cfg:      PricingConfig{ServiceRate: 5, TaxRate: 10, RoundingUnit: 100},
+			want:     OrderCharges{Subtotal: 100000, ServiceRate: 5, ServiceCharge: 5000, TaxRate: 10, Tax: 10500, Total: 115500},
+		},
+		{
+			name:     "PB1 on subtotal plus service, total is not rounded",
+			subtotal: 18500,
+			cfg:      PricingConfig{ServiceRate: 5, TaxRate: 10, RoundingUnit: 500},
+			want:     OrderCharges{Subtotal: 18500, ServiceRate: 5, ServiceCharge: 925, TaxRate: 10, Tax: 1943, Total: 21368},
+		},
+		{
+			name:     "no service charge",
+			subtotal: 36000,
+			cfg:      PricingConfig{ServiceRate: 0, TaxRate: 10, RoundingUnit: 100},
+			want:     OrderCharges{Subtotal: 36000, TaxRate: 10, Tax: 3600, Total: 39600},
+		},
+		{
+			name:     "zero rates keep subtotal",
+			subtotal: 12345,
+			cfg:      PricingConfig{RoundingUnit: 100},
+			want:     OrderCharges{Subtotal: 12345, Total: 12345},
+		},
+		{
+			name:     "zero subtotal",
+			subtotal: 0,
+			cfg:      PricingConfig{ServiceRate: 5, TaxRate: 10, RoundingUnit: 500},
+			want:     OrderCharges{ServiceRate: 5, TaxRate: 10},
+		},
+		{
+			name:     "half rupiah charges round up, small total stays payable",
+			subtotal: 10,
+			cfg:      PricingConfig{ServiceRate: 5, TaxRate: 10, RoundingUnit: 100},
+			want:     OrderCharges{Subtotal: 10, ServiceRate: 5, ServiceCharge: 1, TaxRate: 10, Tax: 1, Total: 12},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyCharges(tt.subtotal, tt.cfg)
+			if got != tt.want {
+				t.Errorf("ApplyCharges(%d, %+v)\n got  %+v\n want %+v", tt.subtotal, tt.cfg, got, tt.want)
+			}
+			if got.Subtotal+got.ServiceCharge+got.Tax != got.Total {
+				t.Errorf("charges do not add up to total: %+v", got)
+			}
+		})
+	}
+}
+
+func TestCashDue(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining models.Money
+		unit      models.Money
+		want      models.Money
+	}{
+		{"already round", 115500, 100, 115500},
+		{"rounded up to 100", 21368, 100, 21400},
+		{"rounded up to 500", 21368, 500, 21500},
+		{"rounded down to 100", 12345, 100, 12300},
+		{"half unit rounds up to 100", 1250, 100, 1300},
+		{"half unit rounds up to 500", 1250, 500, 1500},
+		{"just below half unit rounds down to 500", 1249, 500, 1000},
+		{"below half unit is paid exactly", 12, 100, 12},
+		{"no rounding unit", 21368, 0, 21368},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CashDue(tt.remaining, tt.unit); got != tt.want {
+				t.Errorf("CashDue(%d, %d) = %d, want %d", tt.remaining, tt.unit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPricingConfigRoundingUnit(t *testing.T) {
+	tests := []struct {
+		env  string
+		want models.Money
+	}{
+		{"", 100},
+		{"100", 100},
+		{"500", 500},
+		{"250", 100},
+		{"0", 100},
+		{"abc", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run("CASH_ROUNDING="+tt.env, func(t *testing.T) {
+			t.Setenv("CASH_ROUNDING", tt.env)
+			if got := GetPricingConfig().RoundingUnit; got != tt.want {
+				t.Errorf("RoundingUnit = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,14 +3,46 @@ package helper
 import (
 	"os"
 	"strconv"
+	"time"
+
+	"titik-rindang/src/models"
 )
 
-func GetReservationFee() float64 {
+func GetReservationFee() models.Money {
 	feeStr := os.Getenv("RESERVATION_FEE")
-	fee, err := strconv.ParseFloat(feeStr, 64)
+	fee, err := models.ParseMoney(feeStr)
 	if err != nil {
 		return 0 //fallback if not setted
 	}
 
 	return  fee
-}
\ No newline at end of file
+}
+
+// Lama satu reservasi menempati meja (RESERVATION_DURATION, dalam menit)
+func GetReservationDuration() time.Duration {
+	return time.Duration(getEnvInt("RESERVATION_DURATION", 120)) * time.Minute
+}
+
+// Batas lama reservasi yang boleh diminta (MAX_RESERVATION_DURATION, dalam menit)
+func GetMaxReservationDuration() time.Duration {
+	return time.Duration(getEnvInt("MAX_RESERVATION_DURATION", 480)) * time.Minute
+}
+
+// Jarak antar slot di endpoint availability (RESERVATION_SLOT_INTERVAL, dalam menit)
+func GetReservationSlotInterval() time.Duration {
+	return time.Duration(getEnvInt("RESERVATION_SLOT_INTERVAL", 60)) * time.Minute
+}
+
+// Jam buka & tutup cafe (OPEN_HOUR, CLOSE_HOUR)
+func GetOpeningHours() (int, int) {
+	return getEnvInt("OPEN_HOUR", 8), getEnvInt("CLOSE_HOUR", 22)
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Token acak hex, dipakai untuk link/ID yang tidak boleh ditebak
+func RandomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Hash token acak sebelum disimpan ke database (cukup SHA-256 karena token sudah acak panjang)
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Umur access token JWT (ACCESS_TOKEN_TTL_MINUTES)
+func GetAccessTokenTTL() time.Duration {
+	return time.Duration(getEnvInt("ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute
+}
+
+// Umur refresh token sejak terakhir dipakai (REFRESH_TOKEN_TTL_HOURS)
+func GetRefreshTokenTTL() time.Duration {
+	return time.Duration(getEnvInt("REFRESH_TOKEN_TTL_HOURS", 24)) * time.Hour
+}
+
+// Umur token reset password (PASSWORD_RESET_TTL_MINUTES)
+func GetPasswordResetTTL() time.Duration {
+	return time.Duration(getEnvInt("PASSWORD_RESET_TTL_MINUTES", 30)) * time.Minute
+}
@@ -0,0 +1,126 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/models"
+	"titik-rindang/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Salin body response supaya ID entity yang baru dibuat bisa dibaca setelah handler selesai
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// Catat aksi ke audit log kalau request berhasil (status < 400).
+// model menentukan entity (nama tabel) yang di-snapshot sebelum & sesudah handler, param adalah
+// nama path param berisi ID-nya. Tanpa param (create), ID diambil dari response.
+// model nil berarti tidak ada entity yang di-snapshot.
+func Audit(action string, model interface{}, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		svc := services.NewAuditService(database.DB)
+
+		entity := ""
+		if model != nil {
+			entity = svc.EntityName(model)
+		}
+
+		entityID := ""
+		if param != "" {
+			entityID = c.Param(param)
+		}
+
+		var before json.RawMessage
+		if model != nil {
+			before = svc.Snapshot(model, entityID)
+		}
+
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		if entityID == "" {
+			entityID = responseEntityID(writer.body.Bytes())
+		}
+
+		var after json.RawMessage
+		if model != nil {
+			after = svc.Snapshot(model, entityID)
+		}
+
+		role, _ := c.Get("role")
+		roleStr, _ := role.(string)
+
+		entry := models.AuditLog{
+			Actor:      CurrentUsername(c),
+			ActorRole:  roleStr,
+			Action:     action,
+			Entity:     entity,
+			EntityID:   entityID,
+			Before:     before,
+			After:      after,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			StatusCode: c.Writer.Status(),
+			IP:         c.ClientIP(),
+		}
+		if err := svc.Record(&entry); err != nil {
+			log.Printf("failed to record audit log %s: %v", action, err)
+		}
+	}
+}
+
+// ID dari response JSON: {"id": ...}, {"data": {"ID": ...}} atau {"role": {"ID": ...}}
+func responseEntityID(body []byte) string {
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	if id := idValue(response); id != "" {
+		return id
+	}
+	if data, ok := response["data"].(map[string]interface{}); ok {
+		if id := idValue(data); id != "" {
+			return id
+		}
+	}
+	for _, value := range response {
+		if object, ok := value.(map[string]interface{}); ok {
+			if id := idValue(object); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+func idValue(object map[string]interface{}) string {
+	for _, key := range []string{"id", "ID"} {
+		switch id := object[key].(type) {
+		case string:
+			return id
+		case float64:
+			return fmt.Sprintf("%.0f", id)
+		}
+	}
+	return ""
+}
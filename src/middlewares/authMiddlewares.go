@@ -6,6 +6,9 @@ import (
 	"os"
 	"strings"
 	"time"
+	"titik-rindang/src/database"
+	"titik-rindang/src/helper"
+	"titik-rindang/src/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -23,12 +26,15 @@ func getSecretKey() string {
 	return secret
 }
 
-// Generate token JWT
-func GenerateToken(username, role string) (string, error) {
+// Generate access token JWT (umur pendek), terikat ke sesi supaya bisa dicabut
+func GenerateToken(username, role string, sessionID uint) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"username": username,
 		"role":     role,
-		"exp":      time.Now().Add(time.Hour * 3).Unix(),
+		"sid":      sessionID,
+		"iat":      now.Unix(),
+		"exp":      now.Add(helper.GetAccessTokenTTL()).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(secretKey)
@@ -68,46 +74,65 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Tolak token dari sesi yang sudah logout / dicabut
+		sid, ok := claims["sid"].(float64)
+		if !ok || !services.NewAuthService(database.DB).SessionActive(uint(sid)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked, please login again"})
+			c.Abort()
+			return
+		}
+
 		// Simpan ke context
 		c.Set("username", claims["username"])
 		c.Set("role", claims["role"])
+		c.Set("session_id", uint(sid))
 
 		c.Next()
 	}
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+// Pindahkan ?token= ke header Authorization, khusus endpoint streaming
+func QueryTokenMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access forbidden: Admin only"})
-			c.Abort()
-			return
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
 		}
 		c.Next()
 	}
 }
 
-func CashierMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "cashier" && role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access forbidden"})
-			c.Abort()
-			return
+// Username dari token, "guest" untuk endpoint public
+func CurrentUsername(c *gin.Context) string {
+	if username, ok := c.Get("username"); ok {
+		if name, ok := username.(string); ok && name != "" {
+			return name
+		}
+	}
+	return "guest"
+}
+
+// ID sesi dari token, 0 kalau tidak ada
+func CurrentSessionID(c *gin.Context) uint {
+	if sid, ok := c.Get("session_id"); ok {
+		if id, ok := sid.(uint); ok {
+			return id
 		}
-		c.Next()
 	}
+	return 0
 }
 
-func StaffMiddleware() gin.HandlerFunc {
+// Route hanya boleh diakses role yang punya permission tsb (lihat /admin/roles)
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "staff" && role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access forbidden: Admin only"})
+		role, _ := c.Get("role")
+		roleStr, _ := role.(string)
+		if !services.NewRoleService(database.DB).HasPermission(roleStr, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access forbidden: missing permission " + permission})
 			c.Abort()
 			return
 		}
 		c.Next()
 	}
-}
\ No newline at end of file
+}
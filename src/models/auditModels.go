@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrAuditImmutable = errors.New("audit log is append-only")
+
+// Jejak setiap aksi yang mengubah data, hanya boleh ditambah (tidak diubah / dihapus)
+type AuditLog struct {
+	ID         uint            `gorm:"primaryKey"`
+	Actor      string          `gorm:"type:varchar(100);index"` // username, "guest" untuk endpoint public
+	ActorRole  string          `gorm:"type:varchar(50)"`
+	Action     string          `gorm:"type:varchar(50);index"`                  // contoh: order.delete, user.update
+	Entity     string          `gorm:"type:varchar(50);index:idx_audit_entity"` // nama tabel
+	EntityID   string          `gorm:"type:varchar(64);index:idx_audit_entity"`
+	Before     json.RawMessage `gorm:"type:jsonb"`
+	After      json.RawMessage `gorm:"type:jsonb"`
+	Method     string          `gorm:"type:varchar(10)"`
+	Path       string          `gorm:"type:varchar(255)"`
+	StatusCode int
+	IP         string    `gorm:"type:varchar(45)"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (AuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditImmutable
+}
+
+func (AuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditImmutable
+}
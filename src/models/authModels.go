@@ -12,9 +12,42 @@ type Auth struct {
 	Email			string			`gorm:"unique"`
 	Password		string			`gorm:"not null"`
 	Role			string			`gorm:"default:cashier"`
-	Status			string
+	Status			string			`gorm:"default:active"` // active, suspended, inactive
 	ResetAllowed	bool			`gorm:"default:false"`
+	FailedLogins	int				`gorm:"default:0"`
+	LockedUntil		*time.Time
 	CreatedAt		time.Time
 	UpdatedAt		time.Time
 	DeletedAt		gorm.DeletedAt	`gorm:"index"`
-}
\ No newline at end of file
+}
+
+const (
+	UserActive    = "active"
+	UserSuspended = "suspended"
+	UserInactive  = "inactive"
+)
+
+// Sesi login, refresh token hanya disimpan dalam bentuk hash
+type Session struct {
+	ID					uint			`gorm:"primaryKey"`
+	UserID				string			`gorm:"index; not null"`
+	RefreshTokenHash	string			`gorm:"uniqueIndex; not null" json:"-"`
+	PreviousTokenHash	string			`gorm:"index" json:"-"`
+	UserAgent			string
+	IP					string
+	ExpiresAt			time.Time
+	LastUsedAt			*time.Time
+	RevokedAt			*time.Time
+	CreatedAt			time.Time
+	UpdatedAt			time.Time
+}
+
+// Token reset password sekali pakai, hanya bisa dibuat kalau admin mengizinkan (ResetAllowed)
+type PasswordResetToken struct {
+	ID			uint		`gorm:"primaryKey"`
+	UserID		string		`gorm:"index; not null"`
+	TokenHash	string		`gorm:"uniqueIndex; not null" json:"-"`
+	ExpiresAt	time.Time
+	UsedAt		*time.Time
+	CreatedAt	time.Time
+}
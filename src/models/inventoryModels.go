@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Bahan baku: biji kopi, susu, sirup, dst.
+type Ingredient struct {
+	ID                uint    `gorm:"primaryKey"`
+	Name              string  `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Unit              string  `gorm:"type:varchar(10);not null"` // g, ml, pcs
+	Stock             float64 `gorm:"not null;default:0"`
+	LowStockThreshold float64 `gorm:"not null;default:0"` // alert kalau stok <= batas ini
+	LastUnitCost      Money   `gorm:"not null;default:0"` // harga per satuan dari penerimaan barang terakhir
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"` // soft delete, riwayat stok tetap menunjuk ke bahan ini
+}
+
+func (i Ingredient) IsLow() bool {
+	return i.Stock <= i.LowStockThreshold
+}
+
+// Takaran bahan untuk satu porsi menu
+type RecipeItem struct {
+	ID           uint       `gorm:"primaryKey"`
+	MenuID       uint       `gorm:"not null;uniqueIndex:idx_recipe_menu_ingredient"`
+	IngredientID uint       `gorm:"not null;uniqueIndex:idx_recipe_menu_ingredient"`
+	Ingredient   Ingredient `gorm:"foreignKey:IngredientID" json:",omitempty"`
+	Quantity     float64    `gorm:"not null"`
+}
+
+// Riwayat keluar-masuk stok
+type StockMovement struct {
+	ID           uint    `gorm:"primaryKey"`
+	IngredientID uint    `gorm:"not null;index"`
+	Change       float64 `gorm:"not null"` // minus = keluar
+	StockAfter   float64 `gorm:"not null"`
+	Reason       string  `gorm:"type:varchar(20);not null"` // order, restock, waste, adjustment
+	Note         string  `gorm:"type:text"`
+	OrderID      *uint   `gorm:"index"`
+	Actor        string  `gorm:"type:varchar(100)"`
+	CreatedAt    time.Time
+}
+
+const (
+	StockOrder      = "order"
+	StockRestock    = "restock"
+	StockWaste      = "waste"
+	StockAdjustment = "adjustment"
+)
@@ -7,7 +7,7 @@ type Invoice struct {
 	ReservationID  uint        `gorm:"not null"`
 	Reservation    Reservation `gorm:"foreignKey:ReservationID"`
 	InvoiceNumber  string      `gorm:"unique;not null"`
-	AmountPaid     float64     `gorm:"not null"`
+	AmountPaid     Money       `gorm:"not null"`
 	PaymentMethod  string      `gorm:"type:varchar(50)"`
 	CreatedAt      time.Time
 	UpdatedAt	   time.Time
@@ -1,13 +1,140 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Menu struct {
 	ID			uint		`gorm:"primarykey"`
 	Name		string		`gorm:"type:varchar(100);not null"`
 	Tagline		string		`gorm:"type:varchar(150)"`
-	ImageURL	string		`gorm:"type:text"`
-	Price		float64		`gorm:"not null"`
+	ImageURL	string		`gorm:"type:text"` // sama dengan varian "full"
+	Images		MenuImages	`gorm:"type:text"` // URL per varian: thumb, card, full
+	Price		Money		`gorm:"not null"`
+	Station		string		`gorm:"type:varchar(20);default:'kitchen'"` // kitchen, bar
+	CategoryID	*uint		`gorm:"index"`
+	Category	*MenuCategory	`gorm:"foreignKey:CategoryID" json:",omitempty"`
+	Position	int			`gorm:"not null;default:0"` // urutan di dalam kategori
+	ModifierGroups	[]ModifierGroup	`gorm:"many2many:menu_modifier_groups" json:",omitempty"`
+	IsAvailable	bool		`gorm:"not null"` // false = habis / sold out, menu baru diisi true oleh controller
+	AvailableFrom	string	`gorm:"type:varchar(5)"` // "07:00", kosong = tanpa batas
+	AvailableUntil	string	`gorm:"type:varchar(5)"` // "11:00", kosong = tanpa batas
+	SoldOutByStock	bool	`gorm:"not null;default:false"` // sold out otomatis karena bahan habis
+	Recipe		[]RecipeItem	`gorm:"foreignKey:MenuID" json:",omitempty"`
+	AvailableNow	bool		`gorm:"-"` // dihitung saat data dibaca
 	CreatedAt	time.Time
 	UpdatedAt	time.Time
-}
\ No newline at end of file
+}
+
+func (m *Menu) AfterFind(tx *gorm.DB) error {
+	m.AvailableNow = m.UnavailableReason(time.Now()) == ""
+
+	// menu lama hanya punya satu gambar, dipakai untuk semua varian
+	if len(m.Images) == 0 && m.ImageURL != "" {
+		m.Images = MenuImages{"thumb": m.ImageURL, "card": m.ImageURL, "full": m.ImageURL}
+	}
+	return nil
+}
+
+// Disimpan sebagai JSON di kolom text
+type MenuImages map[string]string
+
+func (i MenuImages) Value() (driver.Value, error) {
+	if len(i) == 0 {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(i)
+	return string(raw), err
+}
+
+func (i *MenuImages) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		raw = nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into MenuImages", value)
+	}
+
+	*i = MenuImages{}
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, i)
+}
+
+// Alasan menu tidak bisa dipesan pada jam t, kosong berarti bisa dipesan
+func (m Menu) UnavailableReason(t time.Time) string {
+	if !m.IsAvailable {
+		return "sold out"
+	}
+	if m.AvailableFrom == "" && m.AvailableUntil == "" {
+		return ""
+	}
+
+	from, until := m.AvailableFrom, m.AvailableUntil
+	if from == "" {
+		from = "00:00"
+	}
+	if until == "" {
+		until = "24:00"
+	}
+
+	// format HH:MM bisa dibandingkan sebagai string
+	now := t.Format("15:04")
+	inside := now >= from && now < until
+	if from > until { // lewat tengah malam, contoh 22:00-02:00
+		inside = now >= from || now < until
+	}
+	if !inside {
+		return "only available " + from + "-" + until
+	}
+	return ""
+}
+
+// Kategori menu: coffee, non-coffee, food, dessert, dst.
+type MenuCategory struct {
+	ID			uint		`gorm:"primarykey"`
+	Name		string		`gorm:"type:varchar(50);uniqueIndex;not null"`
+	Position	int			`gorm:"not null;default:0"` // urutan tampil di halaman menu
+	CreatedAt	time.Time
+	UpdatedAt	time.Time
+	Menus		[]Menu		`gorm:"foreignKey:CategoryID" json:",omitempty"`
+}
+
+// Grup pilihan yang bisa dipasang ke beberapa menu: Size, Sugar, Ice, Add-on
+type ModifierGroup struct {
+	ID			uint		`gorm:"primarykey"`
+	Name		string		`gorm:"type:varchar(50);not null"`
+	Required	bool		`gorm:"not null;default:false"`
+	MinSelect	int			`gorm:"not null;default:0"`
+	MaxSelect	int			`gorm:"not null"` // 0 = tanpa batas, default 1 diisi controller
+	Options		[]ModifierOption	`gorm:"foreignKey:GroupID"`
+	CreatedAt	time.Time
+	UpdatedAt	time.Time
+}
+
+// Minimal pilihan yang wajib diambil customer
+func (g ModifierGroup) MinRequired() int {
+	if g.Required && g.MinSelect < 1 {
+		return 1
+	}
+	return g.MinSelect
+}
+
+type ModifierOption struct {
+	ID			uint		`gorm:"primarykey"`
+	GroupID		uint		`gorm:"not null;index"`
+	Name		string		`gorm:"type:varchar(50);not null"`
+	PriceDelta	Money		`gorm:"not null;default:0"` // tambahan harga, bisa 0
+	Position	int			`gorm:"not null;default:0"`
+}
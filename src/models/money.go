@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Nominal uang dalam rupiah penuh (tanpa sen), disimpan sebagai bigint.
+// Di JSON tampil sebagai angka biasa, contoh: 25000.
+type Money int64
+
+// Parse rupiah penuh: angka saja ("25000") atau dengan titik sebagai pemisah ribuan
+// ("25.000", "1.250.000"). Titik selalu dianggap pemisah ribuan, jadi desimal ("25000.00",
+// "25000,00"), koma, tanda +/-, atau kelompok yang salah ("0.500", "25.00", ".") ditolak.
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, errors.New("empty amount")
+	}
+
+	if strings.Contains(value, ".") {
+		if !isThousandsGrouped(value) {
+			return 0, errors.New("invalid amount: use 25000 or 25.000")
+		}
+		value = strings.ReplaceAll(value, ".", "")
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return 0, errors.New("invalid amount: use 25000 or 25.000")
+		}
+	}
+
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.New("amount too large")
+	}
+	return Money(amount), nil
+}
+
+// "25.000" / "1.250.000" (pemisah ribuan ala Indonesia), kelompok pertama tanpa nol di depan
+func isThousandsGrouped(value string) bool {
+	parts := strings.Split(value, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for i, part := range parts {
+		if i > 0 && len(part) != 3 {
+			return false
+		}
+	}
+	return len(parts[0]) > 0 && len(parts[0]) <= 3 && parts[0][0] != '0'
+}
+
+// Kalikan dengan jumlah item
+func (m Money) Times(qty int) Money {
+	return m * Money(qty)
+}
+
+// Kalikan dengan jumlah pecahan (contoh 2.5 kg), dibulatkan ke rupiah terdekat
+func (m Money) Multiply(qty float64) Money {
+	return Money(math.Round(float64(m) * qty))
+}
+
+// Persentase dari nominal, dibulatkan ke rupiah terdekat
+func (m Money) Percent(rate float64) Money {
+	return Money(math.Round(float64(m) * rate / 100))
+}
+
+// Bagi rata ke n bagian, dibulatkan ke atas
+func (m Money) SplitCeil(n int) Money {
+	if n <= 1 {
+		return m
+	}
+	return (m + Money(n) - 1) / Money(n)
+}
+
+// Bulatkan ke kelipatan unit terdekat (pembulatan tunai)
+func (m Money) RoundTo(unit Money) Money {
+	if unit <= 0 {
+		return m
+	}
+	return Money(math.Round(float64(m)/float64(unit))) * unit
+}
+
+// Format "Rp 25.000"
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	digits := strconv.FormatInt(value, 10)
+	var grouped []string
+	for len(digits) > 3 {
+		grouped = append([]string{digits[len(digits)-3:]}, grouped...)
+		digits = digits[:len(digits)-3]
+	}
+	grouped = append([]string{digits}, grouped...)
+
+	return sign + "Rp " + strings.Join(grouped, ".")
+}
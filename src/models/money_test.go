@@ -0,0 +1,103 @@
+package models
+
+import "testing"
+
+func TestMoneyRoundTo(t *testing.T) {
+	tests := []struct {
+		amount Money
+		unit   Money
+		want   Money
+	}{
+		{12349, 100, 12300},
+		{12350, 100, 12400},
+		{12300, 100, 12300},
+		{49, 100, 0},
+		{50, 100, 100},
+		{12249, 500, 12000},
+		{12250, 500, 12500},
+		{12500, 500, 12500},
+		{249, 500, 0},
+		{250, 500, 500},
+		{0, 100, 0},
+		{0, 500, 0},
+		{-150, 100, -200},
+		{-149, 100, -100},
+		{12345, 0, 12345},
+		{12345, -100, 12345},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.RoundTo(tt.unit); got != tt.want {
+			t.Errorf("Money(%d).RoundTo(%d) = %d, want %d", tt.amount, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		amount Money
+		rate   float64
+		want   Money
+	}{
+		{100000, 10, 10000},
+		{18500, 5, 925},
+		{19425, 10, 1943},
+		{10, 5, 1},
+		{9, 5, 0},
+		{100000, 0, 0},
+		{0, 10, 0},
+		{100000, 12.5, 12500},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.Percent(tt.rate); got != tt.want {
+			t.Errorf("Money(%d).Percent(%v) = %d, want %d", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	valid := []struct {
+		input string
+		want  Money
+	}{
+		{"25000", 25000},
+		{" 25000 ", 25000},
+		{"0", 0},
+		{"25.000", 25000},
+		{"1.250.000", 1250000},
+		{"999.999", 999999},
+		{"500", 500},
+	}
+	for _, tt := range valid {
+		got, err := ParseMoney(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, %v; want %d", tt.input, got, err, tt.want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		".",
+		"0.500",
+		"25.00",
+		"25000.00",
+		"25000,00",
+		"25.000,00",
+		"25,000",
+		"1.25.000",
+		"1250.000",
+		".500",
+		"500.",
+		"-25000",
+		"+25000",
+		"1e3",
+		"abc",
+		"99999999999999999999",
+	}
+	for _, input := range invalid {
+		if got, err := ParseMoney(input); err == nil {
+			t.Errorf("ParseMoney(%q) = %d, want error", input, got)
+		}
+	}
+}
@@ -1,25 +1,156 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type Order struct {
 	ID        		uint       `gorm:"primaryKey"`
 	TableID   		uint       `gorm:"not null"`                      // dine-in per meja
 	Table     		Table      `gorm:"foreignKey:TableID"`
 	Customer  		string	   `gorm:"type:varchar(100)"`
-	Total     		float64    `gorm:"not null"`
-	Status    		string     `gorm:"type:varchar(20);default:'unpaid'"` // unpaid, paid
+	TrackingToken	string	   `gorm:"type:varchar(64);uniqueIndex"`  // untuk tracking order oleh customer
+	Subtotal		Money	   `gorm:"not null;default:0"`          // jumlah harga item
+	ServiceRate		float64	   `gorm:"not null;default:0"`          // persen
+	ServiceCharge	Money	   `gorm:"not null;default:0"`
+	TaxRate			float64	   `gorm:"not null;default:0"`          // persen PB1
+	Tax				Money	   `gorm:"not null;default:0"`
+	Rounding		Money	   `gorm:"not null;default:0"`          // pembulatan tunai saat pelunasan, bisa minus (tidak termasuk Total)
+	Total     		Money      `gorm:"not null"`
+	Status    		string     `gorm:"type:varchar(20);default:'open'"` // open, served, paid, cancelled, refunded
 	PaymentMethod	string	   `gorm:"type:varchar(50)"`	
 	CreatedAt 		time.Time
 	UpdatedAt 		time.Time
 	OrderItems 		[]OrderItem `gorm:"foreignKey:OrderID"`
+	StatusHistory	[]OrderStatusHistory `gorm:"foreignKey:OrderID" json:",omitempty"`
+	ItemChanges		[]OrderItemChange `gorm:"foreignKey:OrderID" json:",omitempty"`
+	Payments		[]Payment  `gorm:"foreignKey:OrderID"`
 }
 
 type OrderItem struct {
-	ID        uint    `gorm:"primaryKey"`
-	OrderID   uint    `gorm:"not null"`
-	MenuID    uint    `gorm:"not null"`
-	Menu      Menu    `gorm:"foreignKey:MenuID"`
-	Quantity  int     `gorm:"not null"`
-	Subtotal  float64 `gorm:"not null"`
+	ID         uint       `gorm:"primaryKey"`
+	OrderID    uint       `gorm:"not null;index"`
+	MenuID     uint       `gorm:"not null"`
+	Menu       Menu       `gorm:"foreignKey:MenuID"`
+	MenuName   string     `gorm:"type:varchar(100)"`        // snapshot saat order dibuat
+	UnitPrice  Money      `gorm:"not null;default:0"`      // snapshot saat order dibuat
+	Modifiers  ItemModifiers `gorm:"type:text"`             // snapshot saat order dibuat
+	Quantity   int        `gorm:"not null"`
+	Subtotal   Money      `gorm:"not null"`
+	Station    string     `gorm:"type:varchar(20);default:'kitchen'"`      // kitchen, bar
+	PrepStatus string     `gorm:"type:varchar(20);default:'queued';index"` // queued, preparing, ready, served, voided
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	ReadyAt    *time.Time
+	ServedAt   *time.Time
+	VoidedAt   *time.Time
+	VoidReason string     `gorm:"type:text" json:",omitempty"`
+	StockDeducted bool    `gorm:"not null;default:false" json:"-"`
+}
+
+// Harga satu porsi termasuk modifier
+func (i OrderItem) LinePrice() Money {
+	price := i.UnitPrice
+	for _, modifier := range i.Modifiers {
+		price += modifier.PriceDelta
+	}
+	return price
+}
+
+// Jejak perubahan item setelah order dibuat (tambah, ubah qty, void)
+type OrderItemChange struct {
+	ID          uint      `gorm:"primaryKey"`
+	OrderID     uint      `gorm:"not null;index"`
+	OrderItemID uint      `gorm:"not null;index"`
+	MenuName    string    `gorm:"type:varchar(100)"`
+	Action      string    `gorm:"type:varchar(20);not null"` // added, quantity_changed, voided
+	FromQty     int
+	ToQty       int
+	Reason      string    `gorm:"type:text"`
+	Actor       string    `gorm:"type:varchar(100)"`
+	CreatedAt   time.Time
+}
+
+// Riwayat perpindahan status order
+type OrderStatusHistory struct {
+	ID         uint      `gorm:"primaryKey"`
+	OrderID    uint      `gorm:"not null;index"`
+	FromStatus string    `gorm:"type:varchar(20)"`
+	ToStatus   string    `gorm:"type:varchar(20);not null"`
+	Actor      string    `gorm:"type:varchar(100)"`
+	Note       string    `gorm:"type:text"`
+	CreatedAt  time.Time
+}
+
+const (
+	OrderOpen      = "open"
+	OrderServed    = "served"
+	OrderPaid      = "paid"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+const (
+	ItemAdded           = "added"
+	ItemQuantityChanged = "quantity_changed"
+	ItemVoided          = "voided"
+)
+
+const (
+	PrepQueued    = "queued"
+	PrepPreparing = "preparing"
+	PrepReady     = "ready"
+	PrepServed    = "served"
+	PrepVoided    = "voided"
+)
+
+// Pilihan tambahan yang ikut dibekukan di item, contoh: Less Sugar, Extra Shot
+type ItemModifier struct {
+	Group      string
+	Option     string
+	PriceDelta Money
+}
+
+// Disimpan sebagai JSON di kolom text
+type ItemModifiers []ItemModifier
+
+// Ringkasan untuk tiket dapur, contoh: "Large, Less Sugar, Extra Shot"
+func (m ItemModifiers) Summary() string {
+	names := make([]string, 0, len(m))
+	for _, modifier := range m {
+		names = append(names, modifier.Option)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (m ItemModifiers) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return "[]", nil
+	}
+	raw, err := json.Marshal(m)
+	return string(raw), err
+}
+
+func (m *ItemModifiers) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		raw = nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into ItemModifiers", value)
+	}
+
+	*m = ItemModifiers{}
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, m)
 }
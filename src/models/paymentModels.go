@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Tagihan yang dibuat ke payment provider (QRIS / e-wallet)
+type PaymentIntent struct {
+	ID            uint   `gorm:"primaryKey"`
+	Provider      string `gorm:"type:varchar(30);not null"`
+	ProviderRef   string `gorm:"type:varchar(100);uniqueIndex;not null"`
+	OrderID       *uint  `gorm:"index"`
+	ReservationID *uint  `gorm:"index"`
+	Amount        Money  `gorm:"not null"`
+	Method        string `gorm:"type:varchar(30);not null"`          // qris, ewallet
+	Status        string `gorm:"type:varchar(20);default:'pending'"` // pending, paid, failed, expired
+	QRString      string `gorm:"type:text"`
+	RedirectURL   string `gorm:"type:text"`
+	ExpiresAt     time.Time
+	PaidAt        *time.Time
+	// dana sudah diterima provider tapi gagal dicatat ke order / reservasi, perlu dicek manual
+	NeedsReconciliation bool   `gorm:"not null;default:false;index"`
+	ReconcileNote       string `gorm:"type:text"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Metode bayar tunai di kasir, satu-satunya yang kena pembulatan
+const PaymentCash = "cash"
+
+// Satu pembayaran untuk order, satu order bisa dibayar beberapa kali (split bill)
+type Payment struct {
+	ID              uint   `gorm:"primaryKey"`
+	OrderID         uint   `gorm:"not null;index"`
+	Amount          Money  `gorm:"not null"`
+	Method          string `gorm:"type:varchar(50);not null"`
+	SplitType       string `gorm:"type:varchar(20);default:'amount'"` // amount, items, equal, full
+	Shares          int    // jumlah orang untuk split equal
+	PaymentIntentID *uint
+	ReceivedBy      string `gorm:"type:varchar(100)"`
+	CreatedAt       time.Time
+	Items           []PaymentItem `gorm:"foreignKey:PaymentID"`
+}
+
+// Item order yang dilunasi oleh sebuah payment (split per item)
+type PaymentItem struct {
+	ID          uint  `gorm:"primaryKey"`
+	PaymentID   uint  `gorm:"not null;index"`
+	OrderItemID uint  `gorm:"not null;uniqueIndex"`
+	Amount      Money `gorm:"not null"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+type Supplier struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"type:varchar(100);not null"`
+	Phone     string `gorm:"type:varchar(30)"`
+	Email     string `gorm:"type:varchar(100)"`
+	Address   string `gorm:"type:text"`
+	Notes     string `gorm:"type:text"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PurchaseOrder struct {
+	ID         uint       `gorm:"primaryKey"`
+	Number     string     `gorm:"type:varchar(20);uniqueIndex"` // PO-00001
+	SupplierID uint       `gorm:"not null;index"`
+	Supplier   Supplier   `gorm:"foreignKey:SupplierID"`
+	Status     string     `gorm:"type:varchar(20);default:'draft';index"` // draft, ordered, partially_received, received, cancelled
+	Notes      string     `gorm:"type:text"`
+	ExpectedAt *time.Time // perkiraan barang datang
+	Total      Money      `gorm:"not null;default:0"`
+	CreatedBy  string     `gorm:"type:varchar(100)"`
+	OrderedAt  *time.Time
+	ReceivedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Items      []PurchaseOrderItem `gorm:"foreignKey:PurchaseOrderID"`
+	Receipts   []GoodsReceipt      `gorm:"foreignKey:PurchaseOrderID" json:",omitempty"`
+}
+
+type PurchaseOrderItem struct {
+	ID              uint       `gorm:"primaryKey"`
+	PurchaseOrderID uint       `gorm:"not null;index"`
+	IngredientID    uint       `gorm:"not null"`
+	Ingredient      Ingredient `gorm:"foreignKey:IngredientID"`
+	Quantity        float64    `gorm:"not null"`
+	UnitCost        Money      `gorm:"not null;default:0"` // harga per satuan bahan
+	ReceivedQty     float64    `gorm:"not null;default:0"`
+}
+
+func (i PurchaseOrderItem) Subtotal() Money {
+	return i.UnitCost.Multiply(i.Quantity)
+}
+
+// Satu kali penerimaan barang, satu PO bisa diterima bertahap
+type GoodsReceipt struct {
+	ID              uint   `gorm:"primaryKey"`
+	PurchaseOrderID uint   `gorm:"not null;index"`
+	ReceivedBy      string `gorm:"type:varchar(100)"`
+	Note            string `gorm:"type:text"`
+	CreatedAt       time.Time
+	Items           []GoodsReceiptItem `gorm:"foreignKey:GoodsReceiptID"`
+}
+
+type GoodsReceiptItem struct {
+	ID                  uint    `gorm:"primaryKey"`
+	GoodsReceiptID      uint    `gorm:"not null;index"`
+	PurchaseOrderItemID uint    `gorm:"not null;index"`
+	IngredientID        uint    `gorm:"not null"`
+	Quantity            float64 `gorm:"not null"`
+	UnitCost            Money   `gorm:"not null;default:0"` // harga aktual saat barang diterima
+}
+
+const (
+	PurchaseDraft             = "draft"
+	PurchaseOrdered           = "ordered"
+	PurchasePartiallyReceived = "partially_received"
+	PurchaseReceived          = "received"
+	PurchaseCancelled         = "cancelled"
+)
@@ -9,9 +9,13 @@ type Reservation struct {
 	Email            string    `gorm:"type:varchar(100)"`
 	TableID          uint      `gorm:"not null"`
 	Table            Table     `gorm:"foreignKey:TableID"`
-	ReservationDate  time.Time `gorm:"not null"`
-	TableFee         float64   `gorm:"not null"`
+	PartySize        int       `gorm:"not null;default:1"`
+	ReservationDate  time.Time `gorm:"not null;index"`                    // jam mulai
+	Duration         int       `gorm:"not null;default:120"`              // menit
+	EndDate          time.Time `gorm:"index"`                             // jam selesai
+	TableFee         Money     `gorm:"not null"`
 	Status           string    `gorm:"type:varchar(20);default:'Unpaid'"` // Unpaid, Paid, Cancelled
+	PaymentToken     string    `gorm:"type:varchar(64);uniqueIndex"`      // untuk bayar reservasi oleh customer
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
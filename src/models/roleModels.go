@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// Role user, Auth.Role menyimpan Name role ini
+type Role struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string
+	IsSystem    bool         `gorm:"default:false"` // role bawaan, tidak bisa dihapus
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Permission struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string
+}
+
+const (
+	RoleAdmin   = "admin"
+	RoleCashier = "cashier"
+	RoleStaff   = "staff"
+)
+
+// Permission yang dicek di route
+const (
+	PermUserManage       = "user.manage"
+	PermRoleManage       = "role.manage"
+	PermAuditView        = "audit.view"
+	PermReportView       = "report.view"
+	PermMenuEdit         = "menu.edit"
+	PermMenuAvailability = "menu.availability"
+	PermOrderView        = "order.view"
+	PermOrderEdit        = "order.edit"
+	PermOrderVoid        = "order.void"
+	PermOrderPay         = "order.pay"
+	PermOrderStatus      = "order.status"
+	PermOrderDelete      = "order.delete"
+	PermKitchenView      = "kitchen.view"
+	PermKitchenUpdate    = "kitchen.update"
+	PermReservationView  = "reservation.view"
+	PermReservationEdit  = "reservation.edit"
+	PermTableEdit        = "table.edit"
+	PermTableDelete      = "table.delete"
+	PermInventoryView    = "inventory.view"
+	PermInventoryAdjust  = "inventory.adjust"
+	PermInventoryManage  = "inventory.manage"
+	PermPurchaseView     = "purchase.view"
+	PermPurchaseReceive  = "purchase.receive"
+	PermPurchaseManage   = "purchase.manage"
+)
+
+// Semua permission yang dikenal sistem, disinkron ke tabel permissions saat start
+var PermissionCatalog = []Permission{
+	{Name: PermUserManage, Description: "Kelola user: register, edit, hapus, suspend, unlock, izin reset password"},
+	{Name: PermRoleManage, Description: "Kelola role dan permission"},
+	{Name: PermAuditView, Description: "Lihat dan export audit log"},
+	{Name: PermReportView, Description: "Lihat dashboard & laporan"},
+	{Name: PermMenuEdit, Description: "Tambah/edit/hapus menu, kategori dan modifier"},
+	{Name: PermMenuAvailability, Description: "Tandai menu habis / atur jam tersedia"},
+	{Name: PermOrderView, Description: "Lihat order dan cetak struk"},
+	{Name: PermOrderEdit, Description: "Tambah / ubah item order yang masih open"},
+	{Name: PermOrderVoid, Description: "Void item order"},
+	{Name: PermOrderPay, Description: "Terima pembayaran order"},
+	{Name: PermOrderStatus, Description: "Ubah status order (served, cancelled, refunded, dll)"},
+	{Name: PermOrderDelete, Description: "Hapus order"},
+	{Name: PermKitchenView, Description: "Lihat antrian dapur"},
+	{Name: PermKitchenUpdate, Description: "Ubah status masak item"},
+	{Name: PermReservationView, Description: "Lihat reservasi"},
+	{Name: PermReservationEdit, Description: "Ubah / hapus reservasi"},
+	{Name: PermTableEdit, Description: "Tambah / ubah meja"},
+	{Name: PermTableDelete, Description: "Hapus meja"},
+	{Name: PermInventoryView, Description: "Lihat stok bahan, riwayat stok dan resep"},
+	{Name: PermInventoryAdjust, Description: "Koreksi stok bahan (restock, waste, opname)"},
+	{Name: PermInventoryManage, Description: "Kelola bahan dan resep menu"},
+	{Name: PermPurchaseView, Description: "Lihat supplier dan purchase order"},
+	{Name: PermPurchaseReceive, Description: "Terima barang dari purchase order"},
+	{Name: PermPurchaseManage, Description: "Kelola supplier dan purchase order"},
+}
+
+// Permission awal role bawaan (admin selalu punya semua permission)
+var DefaultRolePermissions = map[string][]string{
+	RoleCashier: {
+		PermOrderView, PermOrderEdit, PermOrderVoid, PermOrderPay, PermOrderStatus,
+		PermMenuAvailability, PermReservationView, PermReservationEdit, PermTableEdit,
+	},
+	RoleStaff: {
+		PermOrderView, PermOrderEdit, PermMenuAvailability, PermReservationView, PermTableEdit,
+		PermKitchenView, PermKitchenUpdate, PermInventoryView, PermInventoryAdjust,
+		PermPurchaseView, PermPurchaseReceive,
+	},
+}
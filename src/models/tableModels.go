@@ -1,12 +1,68 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type Table struct {
-	ID			uint	`gorm:"primaryKey"`
-	TableNo		int		`gorm:"unique;not null"`
-	Status		string	`gorm:"type:varchar(20); default:'available'"`
+	ID			uint			`gorm:"primaryKey"`
+	TableNo		int				`gorm:"unique;not null"`
+	Status		string			`gorm:"type:varchar(20); default:'available'"`
 	//available, booked, in_use
+	MinCapacity	int				`gorm:"not null;default:1"`
+	MaxCapacity	int				`gorm:"not null;default:4"`
+	Zone		string			`gorm:"type:varchar(20);default:'indoor'"`
+	//indoor, outdoor, smoking, vip
+	Features	TableFeatures	`gorm:"type:varchar(255)"`
+	//contoh: window, power_outlet, wheelchair
 	CreatedAt	time.Time
 	UpdatedAt	time.Time
-}
\ No newline at end of file
+}
+
+var TableZones = []string{"indoor", "outdoor", "smoking", "vip"}
+
+// Muat jumlah tamu sesuai kapasitas meja
+func (t Table) Fits(partySize int) bool {
+	return partySize >= t.MinCapacity && partySize <= t.MaxCapacity
+}
+
+// Disimpan sebagai teks dipisah koma, tampil di JSON sebagai array
+type TableFeatures []string
+
+func (f TableFeatures) Has(feature string) bool {
+	for _, v := range f {
+		if v == feature {
+			return true
+		}
+	}
+	return false
+}
+
+func (f TableFeatures) Value() (driver.Value, error) {
+	return strings.Join(f, ","), nil
+}
+
+func (f *TableFeatures) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into TableFeatures", value)
+	}
+
+	*f = TableFeatures{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*f = append(*f, item)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package payments
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"titik-rindang/src/models"
+)
+
+// Status pembayaran dari provider
+const (
+	StatusPending = "pending"
+	StatusPaid    = "paid"
+	StatusFailed  = "failed"
+	StatusExpired = "expired"
+)
+
+// Metode yang didukung
+const (
+	MethodQRIS    = "qris"
+	MethodEWallet = "ewallet"
+)
+
+type IntentRequest struct {
+	Reference   string // referensi internal, contoh: ORDER-12 / RSV-3
+	Amount      models.Money
+	Method      string
+	Description string
+}
+
+type Intent struct {
+	ProviderRef string
+	QRString    string
+	RedirectURL string
+	ExpiresAt   time.Time
+}
+
+// Hasil callback yang sudah lolos verifikasi signature
+type WebhookEvent struct {
+	ProviderRef string
+	Status      string
+	Amount      models.Money
+}
+
+// Provider pembayaran (QRIS / e-wallet), bisa diganti lewat PAYMENT_PROVIDER
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(req IntentRequest) (*Intent, error)
+	VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+var (
+	ErrInvalidSignature     = errors.New("invalid webhook signature")
+	ErrWebhookSecretMissing = errors.New("PAYMENT_WEBHOOK_SECRET is not set")
+)
+
+func NewProvider(name string) (PaymentProvider, error) {
+	switch name {
+	case "", "fake":
+		return NewFakeProvider(webhookSecret()), nil
+	default:
+		return nil, errors.New("unknown payment provider: " + name)
+	}
+}
+
+// Provider sesuai env, fallback ke fake provider lokal
+func Default() PaymentProvider {
+	provider, err := NewProvider(os.Getenv("PAYMENT_PROVIDER"))
+	if err != nil {
+		log.Printf("%v, falling back to fake provider", err)
+		return NewFakeProvider(webhookSecret())
+	}
+	return provider
+}
+
+// PAYMENT_PROVIDER diisi dengan provider yang dikenal (fallback ke fake tidak dihitung)
+func ProviderConfigured() bool {
+	name := os.Getenv("PAYMENT_PROVIDER")
+	if name == "" {
+		return false
+	}
+	_, err := NewProvider(name)
+	return err == nil
+}
+
+// Tanpa secret semua callback ditolak, tidak ada secret default yang bisa ditebak
+func webhookSecret() string {
+	return os.Getenv("PAYMENT_WEBHOOK_SECRET")
+}
+
+func WebhookSecretConfigured() bool {
+	return webhookSecret() != ""
+}
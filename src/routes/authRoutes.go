@@ -2,7 +2,10 @@ package routes
 
 import (
 	"titik-rindang/src/controllers"
+	"titik-rindang/src/database"
 	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
+	"titik-rindang/src/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +16,9 @@ func AuthRoutes(router *gin.Engine) {
 	{
 		//Endpoint for login
 		authGroup.POST("/login", controllers.Login)
+		authGroup.POST("/refresh", controllers.RefreshToken)
+		authGroup.POST("/forgot-password", middlewares.Audit("auth.forgot_password", nil, ""), controllers.ForgotPassword)
+		authGroup.POST("/reset-password", middlewares.Audit("auth.reset_password", nil, ""), controllers.ResetPassword)
 
 		//Endpoint where needs auth
 		authGroup.GET("/profile", middlewares.AuthMiddleware(), func(c *gin.Context) {
@@ -26,28 +32,44 @@ func AuthRoutes(router *gin.Engine) {
 		authGroup.GET("/check-login", middlewares.AuthMiddleware(), func (c *gin.Context) {
 			username, _ := c.Get("username")
 			role, _ := c.Get("role")
+			roleStr, _ := role.(string)
 			c.JSON(200, gin.H{
 				"isLoggedIn": true,
 				"username": username,
 				"role": role,
+				"permissions": services.NewRoleService(database.DB).PermissionsOf(roleStr),
 			})
 		})
 
-		authGroup.POST("/logout", middlewares.AuthMiddleware(), func(c *gin.Context) {
-			c.JSON(200, gin.H{"message": "Logout successful"})
-		})
+		authGroup.POST("/logout", middlewares.AuthMiddleware(), middlewares.Audit("auth.logout", nil, ""), controllers.Logout)
 	}
 
 	//admin
-	AdminGroup := router.Group("/admin", middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	AdminGroup := router.Group("/admin", middlewares.AuthMiddleware())
 	{
-		
-		AdminGroup.POST("/register", controllers.Register) //Endpoint for Register
-		AdminGroup.GET("/users", controllers.GetAllUsers)
-		AdminGroup.GET("/users/:id", controllers.GetAllUsersById)
-		AdminGroup.PUT("/users/:id", controllers.UpdateUser)
-		AdminGroup.DELETE("/users/:id", controllers.DeleteUser)
-		AdminGroup.GET("/dashboard", func(c *gin.Context) {
+		users := AdminGroup.Group("/", middlewares.RequirePermission(models.PermUserManage))
+		users.POST("/register", middlewares.Audit("user.create", &models.Auth{}, ""), controllers.Register) //Endpoint for Register
+		users.GET("/users", controllers.GetAllUsers)
+		users.GET("/users/:id", controllers.GetAllUsersById)
+		users.PUT("/users/:id", middlewares.Audit("user.update", &models.Auth{}, "id"), controllers.UpdateUser)
+		users.DELETE("/users/:id", middlewares.Audit("user.delete", &models.Auth{}, "id"), controllers.DeleteUser)
+		users.PUT("/users/:id/reset-allowed", middlewares.Audit("user.reset_allowed", &models.Auth{}, "id"), controllers.SetResetAllowed)
+		users.PUT("/users/:id/suspend", middlewares.Audit("user.suspend", &models.Auth{}, "id"), controllers.SuspendUser)
+		users.PUT("/users/:id/reactivate", middlewares.Audit("user.reactivate", &models.Auth{}, "id"), controllers.ReactivateUser)
+		users.PUT("/users/:id/unlock", middlewares.Audit("user.unlock", &models.Auth{}, "id"), controllers.UnlockUser)
+
+		roles := AdminGroup.Group("/", middlewares.RequirePermission(models.PermRoleManage))
+		roles.GET("/permissions", controllers.GetAllPermissions)
+		roles.GET("/roles", controllers.GetAllRoles)
+		roles.POST("/roles", middlewares.Audit("role.create", &models.Role{}, ""), controllers.CreateRole)
+		roles.PUT("/roles/:id", middlewares.Audit("role.update", &models.Role{}, "id"), controllers.UpdateRole)
+		roles.DELETE("/roles/:id", middlewares.Audit("role.delete", &models.Role{}, "id"), controllers.DeleteRole)
+
+		audit := AdminGroup.Group("/", middlewares.RequirePermission(models.PermAuditView))
+		audit.GET("/audit-logs", controllers.GetAuditLogs)
+		audit.GET("/audit-logs/export", controllers.ExportAuditLogs)
+
+		AdminGroup.GET("/dashboard", middlewares.RequirePermission(models.PermReportView), func(c *gin.Context) {
 			username, _ := c.Get("username")
 			c.JSON(200, gin.H{
 				"message": "Welcome!",
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"titik-rindang/src/controllers"
+	"titik-rindang/src/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func EventRoutes(router *gin.Engine) {
+	// EventSource di browser tidak bisa kirim header, token boleh lewat ?token=
+	router.GET("/events/stream", middlewares.QueryTokenMiddleware(), middlewares.AuthMiddleware(), controllers.StreamEvents)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"titik-rindang/src/controllers"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InventoryRoutes(router *gin.Engine) {
+	inventory := router.Group("/inventory", middlewares.AuthMiddleware())
+
+	// Lihat & koreksi stok
+	inventory.GET("/ingredients", middlewares.RequirePermission(models.PermInventoryView), controllers.GetAllIngredients)
+	inventory.GET("/ingredients/:id/movements", middlewares.RequirePermission(models.PermInventoryView), controllers.GetIngredientMovements)
+	inventory.POST("/ingredients/:id/stock", middlewares.RequirePermission(models.PermInventoryAdjust), middlewares.Audit("inventory.adjust", &models.Ingredient{}, "id"), controllers.AdjustIngredientStock)
+	inventory.GET("/recipes/:menu_id", middlewares.RequirePermission(models.PermInventoryView), controllers.GetMenuRecipe)
+
+	// Kelola bahan & resep
+	inventory.POST("/ingredients", middlewares.RequirePermission(models.PermInventoryManage), middlewares.Audit("ingredient.create", &models.Ingredient{}, ""), controllers.CreateIngredient)
+	inventory.PUT("/ingredients/:id", middlewares.RequirePermission(models.PermInventoryManage), middlewares.Audit("ingredient.update", &models.Ingredient{}, "id"), controllers.UpdateIngredient)
+	inventory.DELETE("/ingredients/:id", middlewares.RequirePermission(models.PermInventoryManage), middlewares.Audit("ingredient.delete", &models.Ingredient{}, "id"), controllers.DeleteIngredient)
+	inventory.PUT("/recipes/:menu_id", middlewares.RequirePermission(models.PermInventoryManage), middlewares.Audit("recipe.update", &models.Menu{}, "menu_id"), controllers.SetMenuRecipe)
+}
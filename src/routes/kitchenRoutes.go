@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"titik-rindang/src/controllers"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func KitchenRoutes(router *gin.Engine) {
+	kitchen := router.Group("/kitchen", middlewares.AuthMiddleware())
+
+	kitchen.GET("/queue", middlewares.RequirePermission(models.PermKitchenView), controllers.GetKitchenQueue)
+	kitchen.PUT("/items/:id/status", middlewares.RequirePermission(models.PermKitchenUpdate), middlewares.Audit("kitchen.item_status", &models.OrderItem{}, "id"), controllers.UpdateOrderItemStatus)
+}
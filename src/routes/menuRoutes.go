@@ -3,6 +3,7 @@ package routes
 import (
 	"titik-rindang/src/controllers"
 	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,8 +12,20 @@ func MenuRoutes(router *gin.Engine) {
 	menu := router.Group("/menu")
 
 	menu.GET("/", controllers.GetAllMenu)
+	menu.GET("/categories", controllers.GetAllMenuCategories)
+	menu.POST("/categories", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu_category.create", &models.MenuCategory{}, ""), controllers.CreateMenuCategory)
+	menu.PUT("/categories/order", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu_category.reorder", nil, ""), controllers.ReorderMenuCategories)
+	menu.PUT("/categories/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu_category.update", &models.MenuCategory{}, "id"), controllers.UpdateMenuCategory)
+	menu.PUT("/categories/:id/menus/order", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu_category.reorder_menus", &models.MenuCategory{}, "id"), controllers.ReorderMenus)
+	menu.DELETE("/categories/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu_category.delete", &models.MenuCategory{}, "id"), controllers.DeleteMenuCategory)
+	menu.GET("/modifier-groups", controllers.GetAllModifierGroups)
+	menu.POST("/modifier-groups", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("modifier_group.create", &models.ModifierGroup{}, ""), controllers.CreateModifierGroup)
+	menu.PUT("/modifier-groups/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("modifier_group.update", &models.ModifierGroup{}, "id"), controllers.UpdateModifierGroup)
+	menu.DELETE("/modifier-groups/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("modifier_group.delete", &models.ModifierGroup{}, "id"), controllers.DeleteModifierGroup)
+	menu.PUT("/:id/availability", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuAvailability), middlewares.Audit("menu.availability", &models.Menu{}, "id"), controllers.SetMenuAvailability)
+	menu.PUT("/:id/modifier-groups", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu.modifier_groups", &models.Menu{}, "id"), controllers.SetMenuModifierGroups)
 	menu.GET("/:id", controllers.GetMenuByID)
-	menu.POST("/", middlewares.AuthMiddleware(), middlewares.AdminMiddleware(), controllers.CreateMenu)
-	menu.PUT("/:id", middlewares.AuthMiddleware(), middlewares.AdminMiddleware(), controllers.UpdateMenu)
-	menu.DELETE("/:id", middlewares.AuthMiddleware(), middlewares.AdminMiddleware(), controllers.DeleteMenu)
+	menu.POST("/", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu.create", &models.Menu{}, ""), controllers.CreateMenu)
+	menu.PUT("/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu.update", &models.Menu{}, "id"), controllers.UpdateMenu)
+	menu.DELETE("/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermMenuEdit), middlewares.Audit("menu.delete", &models.Menu{}, "id"), controllers.DeleteMenu)
 }
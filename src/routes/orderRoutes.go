@@ -3,6 +3,7 @@ package routes
 import (
 	"titik-rindang/src/controllers"
 	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,20 +12,26 @@ func OrderRoutes(router *gin.Engine) {
 	order := router.Group("/order")
 
 	//public endpoint for users
-	order.POST("/", controllers.CreateOrder)
-	order.PUT("/:id/confirm", controllers.ConfirmOrder)
+	order.POST("/", middlewares.Audit("order.create", &models.Order{}, ""), controllers.CreateOrder)
+	order.GET("/track/:token", controllers.TrackOrder)
 
-	// Login required (staff/cashier/admin)
+	// Login required, akses per permission
 	orderAuth := order.Group("/")
 	orderAuth.Use(middlewares.AuthMiddleware())
 
-	orderAuth.GET("/", controllers.GetAllOrders)
-	orderAuth.GET("/:id", controllers.GetOrderByID)
-	orderAuth.GET("/:id/receipt", controllers.PrintReceipt)
+	orderAuth.GET("/", middlewares.RequirePermission(models.PermOrderView), controllers.GetAllOrders)
+	orderAuth.GET("/:id", middlewares.RequirePermission(models.PermOrderView), controllers.GetOrderByID)
+	orderAuth.GET("/:id/receipt", middlewares.RequirePermission(models.PermOrderView), controllers.PrintReceipt)
+	orderAuth.POST("/:id/items", middlewares.RequirePermission(models.PermOrderEdit), middlewares.Audit("order.add_items", &models.Order{}, "id"), controllers.AddOrderItems)
+	orderAuth.PUT("/:id/items/:item_id", middlewares.RequirePermission(models.PermOrderEdit), middlewares.Audit("order.update_item", &models.OrderItem{}, "item_id"), controllers.UpdateOrderItem)
+	orderAuth.POST("/:id/items/:item_id/void", middlewares.RequirePermission(models.PermOrderVoid), middlewares.Audit("order.void_item", &models.OrderItem{}, "item_id"), controllers.VoidOrderItem)
+	orderAuth.PUT("/:id/confirm", middlewares.RequirePermission(models.PermOrderPay), middlewares.Audit("order.confirm", &models.Order{}, "id"), controllers.ConfirmOrder)
+	orderAuth.POST("/:id/payments", middlewares.RequirePermission(models.PermOrderPay), middlewares.Audit("order.pay", &models.Order{}, "id"), controllers.AddOrderPayment)
+	orderAuth.PUT("/:id/status", middlewares.RequirePermission(models.PermOrderStatus), middlewares.Audit("order.status", &models.Order{}, "id"), controllers.ChangeOrderStatus)
 
-	// Only Admin
 	orderAuth.DELETE("/:id",
-		middlewares.AdminMiddleware(),
+		middlewares.RequirePermission(models.PermOrderDelete),
+		middlewares.Audit("order.delete", &models.Order{}, "id"),
 		controllers.DeleteOrder,
 	)
 }
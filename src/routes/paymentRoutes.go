@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"titik-rindang/src/controllers"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PaymentRoutes(router *gin.Engine) {
+	payment := router.Group("/payments")
+
+	//public endpoint for customers
+	payment.POST("/order/:token", middlewares.Audit("payment.create", &models.PaymentIntent{}, ""), controllers.CreateOrderPayment)
+	payment.POST("/reservation/:token", middlewares.Audit("payment.create", &models.PaymentIntent{}, ""), controllers.CreateReservationPayment)
+	payment.GET("/:ref", controllers.GetPaymentStatus)
+
+	// callback dari provider, diverifikasi lewat signature
+	payment.POST("/webhook", middlewares.Audit("payment.webhook", &models.PaymentIntent{}, ""), controllers.PaymentWebhook)
+
+	// dana masuk yang gagal dibukukan ke order / reservasi
+	payment.GET("/reconciliation", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermReportView), controllers.GetUnreconciledPayments)
+
+	// fake provider: kasir bisa menandai tagihan lunas saat development
+	payment.POST("/fake/:ref/pay", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermOrderPay), middlewares.Audit("payment.simulate", &models.PaymentIntent{}, ""), controllers.SimulatePayment)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"titik-rindang/src/controllers"
+	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PurchaseRoutes(router *gin.Engine) {
+	purchasing := router.Group("/purchasing", middlewares.AuthMiddleware())
+
+	// Lihat PO & terima barang
+	purchasing.GET("/suppliers", middlewares.RequirePermission(models.PermPurchaseView), controllers.GetAllSuppliers)
+	purchasing.GET("/orders", middlewares.RequirePermission(models.PermPurchaseView), controllers.GetAllPurchaseOrders)
+	purchasing.GET("/orders/:id", middlewares.RequirePermission(models.PermPurchaseView), controllers.GetPurchaseOrderByID)
+	purchasing.GET("/orders/:id/pdf", middlewares.RequirePermission(models.PermPurchaseView), controllers.PrintPurchaseOrder)
+	purchasing.POST("/orders/:id/receive", middlewares.RequirePermission(models.PermPurchaseReceive), middlewares.Audit("purchase_order.receive", &models.PurchaseOrder{}, "id"), controllers.ReceivePurchaseOrder)
+
+	// Kelola supplier & PO
+	purchasing.POST("/suppliers", middlewares.RequirePermission(models.PermPurchaseManage), middlewares.Audit("supplier.create", &models.Supplier{}, ""), controllers.CreateSupplier)
+	purchasing.PUT("/suppliers/:id", middlewares.RequirePermission(models.PermPurchaseManage), middlewares.Audit("supplier.update", &models.Supplier{}, "id"), controllers.UpdateSupplier)
+	purchasing.DELETE("/suppliers/:id", middlewares.RequirePermission(models.PermPurchaseManage), middlewares.Audit("supplier.delete", &models.Supplier{}, "id"), controllers.DeleteSupplier)
+	purchasing.POST("/orders", middlewares.RequirePermission(models.PermPurchaseManage), middlewares.Audit("purchase_order.create", &models.PurchaseOrder{}, ""), controllers.CreatePurchaseOrder)
+	purchasing.PUT("/orders/:id", middlewares.RequirePermission(models.PermPurchaseManage), middlewares.Audit("purchase_order.update", &models.PurchaseOrder{}, "id"), controllers.UpdatePurchaseOrder)
+	purchasing.PUT("/orders/:id/status", middlewares.RequirePermission(models.PermPurchaseManage), middlewares.Audit("purchase_order.status", &models.PurchaseOrder{}, "id"), controllers.ChangePurchaseOrderStatus)
+}
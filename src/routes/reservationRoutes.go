@@ -3,6 +3,7 @@ package routes
 import (
 	"titik-rindang/src/controllers"
 	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,11 +11,12 @@ import (
 func ReservationRoutes(router *gin.Engine) {
 	reservation := router.Group("/reservation")
 
-	reservation.GET("/", middlewares.AuthMiddleware(), controllers.GetAllReservations)
-	reservation.GET("/:id", middlewares.AuthMiddleware(), controllers.GetReservationByID)
+	reservation.GET("/", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermReservationView), controllers.GetAllReservations)
+	reservation.GET("/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermReservationView), controllers.GetReservationByID)
 	reservation.GET("/fee", controllers.GetReservationFee)
-	reservation.POST("/", controllers.CreateReservation)
-	reservation.POST("/confirm/:id", controllers.ConfirmReservation)
-	reservation.PUT("/:id", middlewares.AuthMiddleware(), middlewares.CashierMiddleware(), controllers.UpdateReservation)
-	reservation.DELETE("/:id", middlewares.AuthMiddleware(), middlewares.CashierMiddleware(), controllers.DeleteReservation)
+	reservation.GET("/availability", controllers.GetReservationAvailability)
+	reservation.POST("/", middlewares.Audit("reservation.create", &models.Reservation{}, ""), controllers.CreateReservation)
+	reservation.POST("/confirm/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermOrderPay), middlewares.Audit("reservation.confirm", &models.Reservation{}, "id"), controllers.ConfirmReservation)
+	reservation.PUT("/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermReservationEdit), middlewares.Audit("reservation.update", &models.Reservation{}, "id"), controllers.UpdateReservation)
+	reservation.DELETE("/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermReservationEdit), middlewares.Audit("reservation.delete", &models.Reservation{}, "id"), controllers.DeleteReservation)
 }
\ No newline at end of file
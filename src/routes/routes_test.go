@@ -0,0 +1,101 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Endpoint yang memang boleh dipanggil tanpa login (customer, provider, login)
+var publicMutatingRoutes = map[string]bool{
+	"POST /auth/login":                  true,
+	"POST /auth/refresh":                true,
+	"POST /auth/forgot-password":        true,
+	"POST /auth/reset-password":         true,
+	"POST /order/":                      true,
+	"POST /reservation/":                true,
+	"POST /payments/order/:token":       true,
+	"POST /payments/reservation/:token": true,
+	"POST /payments/webhook":            true,
+}
+
+// Endpoint yang cukup login saja, berlaku untuk semua role
+var authOnlyMutatingRoutes = map[string]bool{
+	"POST /auth/logout": true,
+}
+
+var pathParam = regexp.MustCompile(`[:*][^/]+`)
+
+// Nama semua handler (middleware + controller) tiap route, tanpa menjalankannya
+func routeHandlerNames(t *testing.T) map[string][]string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var names []string
+	router.Use(func(c *gin.Context) {
+		names = c.HandlerNames()
+		c.AbortWithStatus(http.StatusNoContent)
+	})
+
+	ReservationRoutes(router)
+	MenuRoutes(router)
+	TableRoutes(router)
+	AuthRoutes(router)
+	OrderRoutes(router)
+	KitchenRoutes(router)
+	EventRoutes(router)
+	PaymentRoutes(router)
+	InventoryRoutes(router)
+	PurchaseRoutes(router)
+
+	chains := map[string][]string{}
+	for _, route := range router.Routes() {
+		names = nil
+		req := httptest.NewRequest(route.Method, pathParam.ReplaceAllString(route.Path, "1"), nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		if names == nil {
+			t.Fatalf("%s %s did not reach its handler chain", route.Method, route.Path)
+		}
+		chains[route.Method+" "+route.Path] = names
+	}
+	return chains
+}
+
+func hasHandler(names []string, name string) bool {
+	for _, n := range names {
+		if strings.Contains(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMutatingRoutesRequirePermission(t *testing.T) {
+	for route, names := range routeHandlerNames(t) {
+		if strings.HasPrefix(route, "GET ") || publicMutatingRoutes[route] {
+			continue
+		}
+		if !hasHandler(names, "middlewares.AuthMiddleware") {
+			t.Errorf("%s is not behind AuthMiddleware", route)
+		}
+		if !authOnlyMutatingRoutes[route] && !hasHandler(names, "middlewares.RequirePermission") {
+			t.Errorf("%s has no RequirePermission", route)
+		}
+	}
+}
+
+func TestMutatingRoutesAreAudited(t *testing.T) {
+	for route, names := range routeHandlerNames(t) {
+		if strings.HasPrefix(route, "GET ") || route == "POST /auth/login" || route == "POST /auth/refresh" {
+			continue
+		}
+		if !hasHandler(names, "middlewares.Audit") {
+			t.Errorf("%s is not audited", route)
+		}
+	}
+}
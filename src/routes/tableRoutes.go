@@ -3,6 +3,7 @@ package routes
 import (
 	"titik-rindang/src/controllers"
 	"titik-rindang/src/middlewares"
+	"titik-rindang/src/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,7 +13,7 @@ func TableRoutes(router *gin.Engine) {
 
 	table.GET("/", controllers.GetAllTables)
 	table.GET("/:id", controllers.GetTableByID)
-	table.POST("/", middlewares.AuthMiddleware(), controllers.CreateTable)
-	table.PUT("/:id", middlewares.AuthMiddleware(), controllers.UpdateTable)
-	table.DELETE("/:id", middlewares.AuthMiddleware(), middlewares.AdminMiddleware(), controllers.DeleteTable)
+	table.POST("/", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermTableEdit), middlewares.Audit("table.create", &models.Table{}, ""), controllers.CreateTable)
+	table.PUT("/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermTableEdit), middlewares.Audit("table.update", &models.Table{}, "id"), controllers.UpdateTable)
+	table.DELETE("/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermTableDelete), middlewares.Audit("table.delete", &models.Table{}, "id"), controllers.DeleteTable)
 }
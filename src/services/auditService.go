@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"titik-rindang/src/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Field rahasia yang tidak ikut disimpan di snapshot audit (dibandingkan tanpa huruf besar & "_")
+var auditRedactedFields = map[string]bool{
+	"password":          true,
+	"refreshtokenhash":  true,
+	"previoustokenhash": true,
+	"tokenhash":         true,
+	"trackingtoken":     true,
+}
+
+type AuditFilter struct {
+	Actor    string
+	Action   string // "order.delete" persis, atau "order" untuk semua aksi order.*
+	Entity   string
+	EntityID string
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	Limit    int
+}
+
+type AuditService struct {
+	DB *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{DB: db}
+}
+
+func (s *AuditService) Record(entry *models.AuditLog) error {
+	return s.DB.Create(entry).Error
+}
+
+// Nama tabel model, dipakai sebagai nama entity di audit log
+func (s *AuditService) EntityName(model interface{}) string {
+	stmt := &gorm.Statement{DB: s.DB}
+	if err := stmt.Parse(model); err != nil {
+		return ""
+	}
+	return stmt.Schema.Table
+}
+
+// Isi entity saat ini (beserta relasi langsungnya) dalam bentuk JSON,
+// nil kalau tidak ada (belum dibuat / sudah dihapus)
+func (s *AuditService) Snapshot(model interface{}, id string) json.RawMessage {
+	if id == "" {
+		return nil
+	}
+	record := reflect.New(reflect.Indirect(reflect.ValueOf(model)).Type()).Interface()
+	if err := s.DB.Preload(clause.Associations).Where("id = ?", id).Take(record).Error; err != nil {
+		return nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil
+	}
+	redactAuditFields(fields)
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func redactAuditFields(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if auditRedactedFields[strings.ToLower(strings.ReplaceAll(key, "_", ""))] {
+				delete(v, key)
+				continue
+			}
+			redactAuditFields(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactAuditFields(item)
+		}
+	}
+}
+
+func (s *AuditService) filtered(f AuditFilter) *gorm.DB {
+	query := s.DB.Model(&models.AuditLog{})
+	if f.Actor != "" {
+		query = query.Where("actor = ?", f.Actor)
+	}
+	if f.Action != "" {
+		if strings.Contains(f.Action, ".") {
+			query = query.Where("action = ?", f.Action)
+		} else {
+			query = query.Where("action LIKE ?", f.Action+".%")
+		}
+	}
+	if f.Entity != "" {
+		query = query.Where("entity = ?", f.Entity)
+	}
+	if f.EntityID != "" {
+		query = query.Where("entity_id = ?", f.EntityID)
+	}
+	if f.From != nil {
+		query = query.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("created_at < ?", *f.To)
+	}
+	return query
+}
+
+// Audit log terbaru dulu, beserta total baris yang cocok dengan filter
+func (s *AuditService) GetLogs(f AuditFilter) ([]models.AuditLog, int64, error) {
+	var total int64
+	if err := s.filtered(f).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AuditLog
+	err := s.filtered(f).
+		Order("id DESC").
+		Offset((f.Page - 1) * f.Limit).
+		Limit(f.Limit).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+// Jalankan fn untuk setiap audit log yang cocok tanpa memuat semuanya ke memori (export CSV)
+func (s *AuditService) EachLog(f AuditFilter, fn func(models.AuditLog) error) error {
+	rows, err := s.filtered(f).Order("id DESC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditLog
+		if err := s.DB.ScanRows(rows, &entry); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"titik-rindang/src/models"
+)
+
+func TestRedactAuditFields(t *testing.T) {
+	var fields interface{}
+	raw := `{
+		"ID": 1,
+		"Username": "kasir1",
+		"Password": "$2a$10$hash",
+		"refresh_token_hash": "abc",
+		"PreviousTokenHash": "def",
+		"token_hash": "ghi",
+		"TrackingToken": "jkl",
+		"Sessions": [{"ID": 7, "RefreshTokenHash": "mno", "IP": "10.0.0.1"}],
+		"Order": {"ID": 3, "tracking_token": "pqr", "Customer": "Budi"}
+	}`
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	redactAuditFields(fields)
+
+	out, _ := json.Marshal(fields)
+	for _, secret := range []string{"$2a$10$hash", "abc", "def", "ghi", "jkl", "mno", "pqr"} {
+		if strings.Contains(string(out), `"`+secret+`"`) {
+			t.Errorf("secret %q still in snapshot: %s", secret, out)
+		}
+	}
+	for _, kept := range []string{"kasir1", "10.0.0.1", "Budi"} {
+		if !strings.Contains(string(out), kept) {
+			t.Errorf("field %q was removed from snapshot: %s", kept, out)
+		}
+	}
+}
+
+func TestSnapshotRedactsSecrets(t *testing.T) {
+	db := openTestDB(t)
+	svc := NewAuditService(db)
+
+	user := models.Auth{ID: "u-1", Username: "kasir1", Email: "kasir1@example.com", Password: "$2a$10$secrethash", Role: "cashier"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	snapshot := string(svc.Snapshot(&models.Auth{}, user.ID))
+	if snapshot == "" || !strings.Contains(snapshot, "kasir1") {
+		t.Fatalf("user snapshot = %q, want user fields", snapshot)
+	}
+	if strings.Contains(snapshot, "Password") || strings.Contains(snapshot, user.Password) {
+		t.Errorf("user snapshot leaks password: %s", snapshot)
+	}
+
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Kopi Susu", 18000)
+	order, err := NewOrderService(db).CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 1}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	snapshot = string(svc.Snapshot(&models.Order{}, fmt.Sprint(order.ID)))
+	if snapshot == "" || !strings.Contains(snapshot, "Budi") {
+		t.Fatalf("order snapshot = %q, want order fields", snapshot)
+	}
+	if strings.Contains(snapshot, "TrackingToken") || strings.Contains(snapshot, order.TrackingToken) {
+		t.Errorf("order snapshot leaks tracking token: %s", snapshot)
+	}
+}
+
+func TestAuditLogIsAppendOnly(t *testing.T) {
+	db := openTestDB(t)
+	svc := NewAuditService(db)
+
+	entry := models.AuditLog{Actor: "admin", Action: "order.delete", Entity: "orders", EntityID: "1", StatusCode: 200}
+	if err := svc.Record(&entry); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	// lewat GORM: ditolak hook model
+	entry.Actor = "someone-else"
+	if err := db.Save(&entry).Error; !errors.Is(err, models.ErrAuditImmutable) {
+		t.Errorf("Save error = %v, want ErrAuditImmutable", err)
+	}
+	if err := db.Model(&entry).Update("actor", "someone-else").Error; !errors.Is(err, models.ErrAuditImmutable) {
+		t.Errorf("Update error = %v, want ErrAuditImmutable", err)
+	}
+	if err := db.Delete(&entry).Error; !errors.Is(err, models.ErrAuditImmutable) {
+		t.Errorf("Delete error = %v, want ErrAuditImmutable", err)
+	}
+
+	// SQL langsung: ditolak trigger database
+	if err := db.Exec("UPDATE audit_logs SET actor = ? WHERE id = ?", "someone-else", entry.ID).Error; err == nil {
+		t.Error("raw UPDATE on audit_logs succeeded, want rejected")
+	}
+	if err := db.Exec("DELETE FROM audit_logs WHERE id = ?", entry.ID).Error; err == nil {
+		t.Error("raw DELETE on audit_logs succeeded, want rejected")
+	}
+
+	var stored models.AuditLog
+	if err := db.First(&stored, entry.ID).Error; err != nil {
+		t.Fatalf("audit log gone after delete attempts: %v", err)
+	}
+	if stored.Actor != "admin" {
+		t.Errorf("actor = %q, want unchanged admin", stored.Actor)
+	}
+}
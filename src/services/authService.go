@@ -0,0 +1,322 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"titik-rindang/src/helper"
+	"titik-rindang/src/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid username or password")
+	ErrAccountLocked       = errors.New("account is locked")
+	ErrAccountInactive     = errors.New("account is not active")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrResetNotAllowed     = errors.New("password reset not allowed")
+	ErrInvalidResetToken   = errors.New("invalid or expired reset token")
+)
+
+type AuthService struct {
+	DB *gorm.DB
+}
+
+func NewAuthService(db *gorm.DB) *AuthService {
+	return &AuthService{DB: db}
+}
+
+// Cek username & password. Salah password terus-menerus mengunci akun sementara,
+// akun yang tidak aktif tidak bisa login walau passwordnya benar.
+func (s *AuthService) Authenticate(username, password string) (*models.Auth, error) {
+	var user models.Auth
+	if err := s.DB.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return &user, ErrAccountLocked
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		if err := s.recordFailedLogin(&user); err != nil {
+			return nil, err
+		}
+		if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+			return &user, ErrAccountLocked
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.Status != models.UserActive {
+		return &user, ErrAccountInactive
+	}
+
+	if user.FailedLogins > 0 || user.LockedUntil != nil {
+		if err := s.DB.Model(&user).Updates(map[string]interface{}{
+			"failed_logins": 0,
+			"locked_until":  nil,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &user, nil
+}
+
+// Tambah hitungan gagal login secara atomik, kunci akun kalau sudah mencapai batas
+func (s *AuthService) recordFailedLogin(user *models.Auth) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(user, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+
+		user.FailedLogins++
+		updates := map[string]interface{}{"failed_logins": user.FailedLogins}
+		if user.FailedLogins >= helper.GetMaxLoginAttempts() {
+			until := time.Now().Add(helper.GetLoginLockout())
+			user.FailedLogins = 0
+			user.LockedUntil = &until
+			updates["failed_logins"] = 0
+			updates["locked_until"] = until
+		}
+		return tx.Model(user).Updates(updates).Error
+	})
+}
+
+// Admin mengubah status akun. Selain active, semua sesi user langsung dicabut.
+func (s *AuthService) SetUserStatus(userID, status string) (*models.Auth, error) {
+	var user models.Auth
+	if err := s.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"status": status}
+	if status == models.UserActive {
+		updates["failed_logins"] = 0
+		updates["locked_until"] = nil
+	}
+	if err := s.DB.Model(&user).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	if status != models.UserActive {
+		if err := s.RevokeUserSessions(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &user, nil
+}
+
+// Buka kunci akun sebelum masa lockout habis
+func (s *AuthService) UnlockUser(userID string) (*models.Auth, error) {
+	var user models.Auth
+	if err := s.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&user).Updates(map[string]interface{}{
+		"failed_logins": 0,
+		"locked_until":  nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Buat sesi baru setelah login, refresh token mentah hanya dikembalikan sekali ke client
+func (s *AuthService) CreateSession(user *models.Auth, userAgent, ip string) (*models.Session, string, error) {
+	token, err := helper.RandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	session := models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: helper.HashToken(token),
+		UserAgent:        userAgent,
+		IP:               ip,
+		ExpiresAt:        time.Now().Add(helper.GetRefreshTokenTTL()),
+	}
+	if err := s.DB.Create(&session).Error; err != nil {
+		return nil, "", err
+	}
+	return &session, token, nil
+}
+
+// Tukar refresh token dengan yang baru (rotasi). Token lama yang dipakai ulang
+// dianggap bocor, sesinya langsung dicabut.
+func (s *AuthService) RotateRefreshToken(token, userAgent, ip string) (*models.Session, *models.Auth, string, error) {
+	hash := helper.HashToken(token)
+	newToken, err := helper.RandomToken(32)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var session models.Session
+	var user models.Auth
+	reused := false
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("refresh_token_hash = ?", hash).
+			First(&session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if tx.Where("previous_token_hash = ?", hash).First(&session).Error == nil {
+				reused = true
+			}
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+		if err := tx.First(&user, "id = ?", session.UserID).Error; err != nil || user.Status != models.UserActive {
+			return ErrInvalidRefreshToken
+		}
+
+		session.PreviousTokenHash = session.RefreshTokenHash
+		session.RefreshTokenHash = helper.HashToken(newToken)
+		session.ExpiresAt = now.Add(helper.GetRefreshTokenTTL())
+		session.LastUsedAt = &now
+		session.UserAgent = userAgent
+		session.IP = ip
+		return tx.Save(&session).Error
+	})
+
+	if reused {
+		_ = s.RevokeSession(session.ID)
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return &session, &user, newToken, nil
+}
+
+// Cabut satu sesi (logout)
+func (s *AuthService) RevokeSession(id uint) error {
+	return s.DB.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// Cabut semua sesi user, contoh: password diganti atau user dihapus
+func (s *AuthService) RevokeUserSessions(userID string) error {
+	return s.DB.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// Sesi masih berlaku: belum logout / dicabut, refresh token belum kedaluwarsa
+// dan akun pemiliknya masih aktif
+func (s *AuthService) SessionActive(id uint) bool {
+	var count int64
+	s.DB.Model(&models.Session{}).
+		Joins("JOIN auths ON auths.id = sessions.user_id AND auths.deleted_at IS NULL").
+		Where("sessions.id = ? AND sessions.revoked_at IS NULL AND sessions.expires_at > ?", id, time.Now()).
+		Where("auths.status = ?", models.UserActive).
+		Count(&count)
+	return count > 0
+}
+
+// Admin mengizinkan / mencabut izin reset password user
+func (s *AuthService) SetResetAllowed(userID string, allowed bool) (*models.Auth, error) {
+	var user models.Auth
+	if err := s.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&user).Update("reset_allowed", allowed).Error; err != nil {
+		return nil, err
+	}
+	if !allowed {
+		// token yang sudah terkirim ikut tidak berlaku
+		s.DB.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", time.Now())
+	}
+	return &user, nil
+}
+
+// Buat token reset untuk user dengan email tsb, token lama yang belum dipakai dibatalkan
+func (s *AuthService) RequestPasswordReset(email string) (*models.Auth, string, error) {
+	var user models.Auth
+	if err := s.DB.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, "", err
+	}
+	if !user.ResetAllowed {
+		return nil, "", ErrResetNotAllowed
+	}
+
+	token, err := helper.RandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: helper.HashToken(token),
+			ExpiresAt: time.Now().Add(helper.GetPasswordResetTTL()),
+		}).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return &user, token, nil
+}
+
+// Ganti password pakai token reset. Token langsung hangus, izin reset dicabut
+// dan semua sesi login user dicabut. Password harus sudah lolos ValidatePassword.
+func (s *AuthService) ResetPassword(token, newPassword string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	var user models.Auth
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var reset models.PasswordResetToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", helper.HashToken(token)).
+			First(&reset).Error; err != nil {
+			return ErrInvalidResetToken
+		}
+
+		now := time.Now()
+		if reset.UsedAt != nil || now.After(reset.ExpiresAt) {
+			return ErrInvalidResetToken
+		}
+		if err := tx.First(&user, "id = ?", reset.UserID).Error; err != nil {
+			return ErrInvalidResetToken
+		}
+		if !user.ResetAllowed {
+			return ErrResetNotAllowed
+		}
+
+		if err := tx.Model(&reset).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"password":      string(hashed),
+			"reset_allowed": false,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.RevokeUserSessions(user.ID)
+}
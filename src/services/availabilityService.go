@@ -0,0 +1,197 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"titik-rindang/src/helper"
+	"titik-rindang/src/models"
+
+	"gorm.io/gorm"
+)
+
+type AvailabilityService struct {
+	DB *gorm.DB
+}
+
+func NewAvailabilityService(db *gorm.DB) *AvailabilityService {
+	return &AvailabilityService{DB: db}
+}
+
+type TimeSlot struct {
+	Start  time.Time      `json:"start"`
+	End    time.Time      `json:"end"`
+	Tables []models.Table `json:"tables"`
+}
+
+// window waktu sebuah meja sedang terpakai
+type occupancy struct {
+	TableID uint
+	Start   time.Time
+	End     time.Time
+}
+
+func overlaps(startA, endA, startB, endB time.Time) bool {
+	return startA.Before(endB) && startB.Before(endA)
+}
+
+var releasedReservationStatuses = []string{"cancelled", "completed"}
+
+// reservasi yang masih menahan meja (belum cancelled/completed)
+func activeReservations(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Reservation{}).
+		Where("LOWER(status) NOT IN ?", releasedReservationStatuses)
+}
+
+// Status yang sudah tidak menahan meja lagi
+func reservationReleased(status string) bool {
+	for _, released := range releasedReservationStatuses {
+		if strings.EqualFold(status, released) {
+			return true
+		}
+	}
+	return false
+}
+
+// Order yang masih jalan dianggap menempati meja sejak dibuat,
+// minimal selama satu durasi reservasi dan tetap sampai sekarang.
+func openOrderWindow(order models.Order, now time.Time) (time.Time, time.Time) {
+	end := order.CreatedAt.Add(helper.GetReservationDuration())
+	if now.After(end) {
+		end = now
+	}
+	return order.CreatedAt, end
+}
+
+// Kumpulkan semua pemakaian meja yang bersinggungan dengan [start, end)
+func (s *AvailabilityService) occupancies(tableID uint, start, end time.Time, excludeReservationID uint) ([]occupancy, error) {
+	var result []occupancy
+
+	query := activeReservations(s.DB).
+		Where("reservation_date < ? AND end_date > ?", end, start)
+	if tableID != 0 {
+		query = query.Where("table_id = ?", tableID)
+	}
+	if excludeReservationID != 0 {
+		query = query.Where("id <> ?", excludeReservationID)
+	}
+
+	var reservations []models.Reservation
+	if err := query.Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range reservations {
+		result = append(result, occupancy{TableID: r.TableID, Start: r.ReservationDate, End: r.EndDate})
+	}
+
+	orderQuery := s.DB.Where("status IN ? AND created_at < ?", activeOrderStatuses, end)
+	if tableID != 0 {
+		orderQuery = orderQuery.Where("table_id = ?", tableID)
+	}
+
+	var orders []models.Order
+	if err := orderQuery.Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, o := range orders {
+		orderStart, orderEnd := openOrderWindow(o, now)
+		if overlaps(orderStart, orderEnd, start, end) {
+			result = append(result, occupancy{TableID: o.TableID, Start: orderStart, End: orderEnd})
+		}
+	}
+
+	return result, nil
+}
+
+// Cek apakah meja kosong di window [start, end)
+func (s *AvailabilityService) IsTableAvailable(tableID uint, start, end time.Time, excludeReservationID uint) (bool, error) {
+	busy, err := s.occupancies(tableID, start, end, excludeReservationID)
+	if err != nil {
+		return false, err
+	}
+	return len(busy) == 0, nil
+}
+
+// Meja kosong yang muat untuk jumlah tamu, dari yang paling kecil
+func (s *AvailabilityService) SuggestTables(partySize int, start, end time.Time) ([]models.Table, error) {
+	var tables []models.Table
+	if err := s.DB.Where("min_capacity <= ? AND max_capacity >= ?", partySize, partySize).
+		Order("max_capacity ASC, table_no ASC").
+		Find(&tables).Error; err != nil {
+		return nil, err
+	}
+
+	busy, err := s.occupancies(0, start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []models.Table{}
+	for _, table := range tables {
+		if !isBusy(busy, table.ID, start, end) {
+			suggestions = append(suggestions, table)
+		}
+	}
+	return suggestions, nil
+}
+
+func isBusy(busy []occupancy, tableID uint, start, end time.Time) bool {
+	for _, o := range busy {
+		if o.TableID == tableID && overlaps(o.Start, o.End, start, end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Daftar meja kosong per slot pada tanggal tertentu.
+// partySize dan zone opsional (0 / "" berarti semua meja).
+func (s *AvailabilityService) GetAvailability(date time.Time, partySize int, zone string) ([]TimeSlot, error) {
+	openHour, closeHour := helper.GetOpeningHours()
+	duration := helper.GetReservationDuration()
+	interval := helper.GetReservationSlotInterval()
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	dayOpen := day.Add(time.Duration(openHour) * time.Hour)
+	dayClose := day.Add(time.Duration(closeHour) * time.Hour)
+
+	query := s.DB.Order("max_capacity ASC, table_no ASC")
+	if partySize > 0 {
+		query = query.Where("min_capacity <= ? AND max_capacity >= ?", partySize, partySize)
+	}
+	if zone != "" {
+		query = query.Where("zone = ?", zone)
+	}
+
+	var tables []models.Table
+	if err := query.Find(&tables).Error; err != nil {
+		return nil, err
+	}
+
+	busy, err := s.occupancies(0, dayOpen, dayClose, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	slots := []TimeSlot{}
+	for start := dayOpen; !start.Add(duration).After(dayClose); start = start.Add(interval) {
+		if start.Before(now) {
+			continue
+		}
+		end := start.Add(duration)
+
+		free := []models.Table{}
+		for _, table := range tables {
+			if !isBusy(busy, table.ID, start, end) {
+				free = append(free, table)
+			}
+		}
+
+		slots = append(slots, TimeSlot{Start: start, End: end, Tables: free})
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"titik-rindang/src/events"
+	"titik-rindang/src/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InventoryService struct {
+	DB *gorm.DB
+}
+
+func NewInventoryService(db *gorm.DB) *InventoryService {
+	return &InventoryService{DB: db}
+}
+
+// Get All Ingredients (lowOnly = hanya yang stoknya menipis)
+func (s *InventoryService) GetAllIngredients(lowOnly bool) ([]models.Ingredient, error) {
+	query := s.DB.Order("name ASC")
+	if lowOnly {
+		query = query.Where("stock <= low_stock_threshold")
+	}
+
+	var ingredients []models.Ingredient
+	err := query.Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (s *InventoryService) GetIngredientByID(id uint) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	if err := s.DB.First(&ingredient, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ingredient not found")
+		}
+		return nil, err
+	}
+	return &ingredient, nil
+}
+
+func validateIngredient(ingredient *models.Ingredient) error {
+	ingredient.Name = strings.TrimSpace(ingredient.Name)
+	ingredient.Unit = strings.TrimSpace(ingredient.Unit)
+	if ingredient.Name == "" || ingredient.Unit == "" {
+		return errors.New("ingredient name and unit are required")
+	}
+	if ingredient.LowStockThreshold < 0 {
+		return errors.New("low stock threshold must not be negative")
+	}
+	return nil
+}
+
+// Create Ingredient, stok awal dicatat sebagai restock
+func (s *InventoryService) CreateIngredient(ingredient *models.Ingredient, actor string) error {
+	if err := validateIngredient(ingredient); err != nil {
+		return err
+	}
+	if ingredient.Stock < 0 {
+		return errors.New("stock must not be negative")
+	}
+
+	// nama bahan yang sudah dihapus tetap terpakai (unique index)
+	var deleted int64
+	if err := s.DB.Unscoped().Model(&models.Ingredient{}).Where("name = ? AND deleted_at IS NOT NULL", ingredient.Name).Count(&deleted).Error; err != nil {
+		return err
+	}
+	if deleted > 0 {
+		return errors.New("an ingredient with this name was deleted before, use another name")
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ingredient).Error; err != nil {
+			return err
+		}
+		if ingredient.Stock == 0 {
+			return nil
+		}
+		return tx.Create(&models.StockMovement{
+			IngredientID: ingredient.ID,
+			Change:       ingredient.Stock,
+			StockAfter:   ingredient.Stock,
+			Reason:       models.StockRestock,
+			Note:         "initial stock",
+			Actor:        actor,
+			CreatedAt:    time.Now(),
+		}).Error
+	})
+}
+
+// Update Ingredient (nama, satuan, batas stok). Stok diubah lewat AdjustStock.
+func (s *InventoryService) UpdateIngredient(id uint, updated *models.Ingredient) (*models.Ingredient, error) {
+	ingredient, err := s.GetIngredientByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated.Name != "" {
+		ingredient.Name = updated.Name
+	}
+	if updated.Unit != "" {
+		ingredient.Unit = updated.Unit
+	}
+	ingredient.LowStockThreshold = updated.LowStockThreshold
+	if err := validateIngredient(ingredient); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Save(ingredient).Error; err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+// Delete Ingredient (soft delete, riwayat stok tetap disimpan), ditolak kalau masih dipakai resep
+func (s *InventoryService) DeleteIngredient(id uint) error {
+	if _, err := s.GetIngredientByID(id); err != nil {
+		return err
+	}
+
+	var used int64
+	if err := s.DB.Model(&models.RecipeItem{}).Where("ingredient_id = ?", id).Count(&used).Error; err != nil {
+		return err
+	}
+	if used > 0 {
+		return errors.New("ingredient is still used in a recipe")
+	}
+
+	return s.DB.Delete(&models.Ingredient{}, id).Error
+}
+
+// Restock / buang / koreksi stok manual
+func (s *InventoryService) AdjustStock(id uint, change float64, reason string, note string, actor string) (*models.Ingredient, error) {
+	switch reason {
+	case models.StockRestock:
+		if change <= 0 {
+			return nil, errors.New("restock amount must be greater than 0")
+		}
+	case models.StockWaste:
+		if change >= 0 {
+			return nil, errors.New("waste amount must be negative")
+		}
+	case models.StockAdjustment:
+		if change == 0 {
+			return nil, errors.New("adjustment must not be 0")
+		}
+	default:
+		return nil, errors.New("reason must be restock, waste or adjustment")
+	}
+
+	var ingredient *models.Ingredient
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		var err error
+		ingredient, err = changeStock(tx, id, change, reason, note, nil, actor)
+		if err != nil {
+			return err
+		}
+		return refreshStockAvailability(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+// Riwayat stok satu bahan, terbaru di atas
+func (s *InventoryService) GetMovements(id uint) ([]models.StockMovement, error) {
+	if _, err := s.GetIngredientByID(id); err != nil {
+		return nil, err
+	}
+
+	var movements []models.StockMovement
+	err := s.DB.Where("ingredient_id = ?", id).Order("created_at DESC, id DESC").Find(&movements).Error
+	return movements, err
+}
+
+// Get Recipe satu menu
+func (s *InventoryService) GetRecipe(menuID uint) ([]models.RecipeItem, error) {
+	if _, err := NewMenuService(s.DB).GetMenuByID(menuID); err != nil {
+		return nil, err
+	}
+
+	var recipe []models.RecipeItem
+	err := s.DB.Preload("Ingredient").Where("menu_id = ?", menuID).Order("id ASC").Find(&recipe).Error
+	return recipe, err
+}
+
+// Set Recipe, menggantikan resep lama
+func (s *InventoryService) SetRecipe(menuID uint, items []models.RecipeItem) ([]models.RecipeItem, error) {
+	if _, err := NewMenuService(s.DB).GetMenuByID(menuID); err != nil {
+		return nil, err
+	}
+
+	seen := map[uint]bool{}
+	for i := range items {
+		if items[i].Quantity <= 0 {
+			return nil, errors.New("recipe quantity must be greater than 0")
+		}
+		if seen[items[i].IngredientID] {
+			return nil, errors.New("ingredient is listed more than once")
+		}
+		seen[items[i].IngredientID] = true
+		if _, err := s.GetIngredientByID(items[i].IngredientID); err != nil {
+			return nil, err
+		}
+		items[i].ID = 0
+		items[i].MenuID = menuID
+	}
+
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		if err := tx.Where("menu_id = ?", menuID).Delete(&models.RecipeItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			if err := tx.Create(&items).Error; err != nil {
+				return err
+			}
+		}
+		return refreshStockAvailability(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetRecipe(menuID)
+}
+
+// Potong stok untuk item order yang belum dipotong (dipanggil saat order served / paid).
+// Item yang di-void tidak memotong stok.
+func deductOrderStock(tx *gorm.DB, orderID uint, actor string) error {
+	var items []models.OrderItem
+	if err := tx.Where("order_id = ? AND prep_status <> ? AND stock_deducted = ?", orderID, models.PrepVoided, false).
+		Find(&items).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	usage := map[uint]float64{}
+	itemIDs := []uint{}
+	for _, item := range items {
+		var recipe []models.RecipeItem
+		if err := tx.Where("menu_id = ?", item.MenuID).Find(&recipe).Error; err != nil {
+			return err
+		}
+		for _, r := range recipe {
+			usage[r.IngredientID] += r.Quantity * float64(item.Quantity)
+		}
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	// kunci bahan dengan urutan tetap supaya tidak deadlock antar order
+	ingredientIDs := make([]uint, 0, len(usage))
+	for id := range usage {
+		ingredientIDs = append(ingredientIDs, id)
+	}
+	sort.Slice(ingredientIDs, func(i, j int) bool { return ingredientIDs[i] < ingredientIDs[j] })
+
+	for _, id := range ingredientIDs {
+		if _, err := changeStock(tx, id, -usage[id], models.StockOrder, "", &orderID, actor); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&models.OrderItem{}).Where("id IN ?", itemIDs).Update("stock_deducted", true).Error; err != nil {
+		return err
+	}
+	if len(ingredientIDs) == 0 {
+		return nil
+	}
+	return refreshStockAvailability(tx)
+}
+
+// Ubah stok + catat riwayat. Stok boleh minus supaya selisih fisik tetap kelihatan.
+func changeStock(tx *gorm.DB, id uint, change float64, reason, note string, orderID *uint, actor string) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ingredient, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ingredient not found")
+		}
+		return nil, err
+	}
+
+	wasLow := ingredient.IsLow()
+	ingredient.Stock += change
+	if err := tx.Model(&ingredient).Update("stock", ingredient.Stock).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Create(&models.StockMovement{
+		IngredientID: ingredient.ID,
+		Change:       change,
+		StockAfter:   ingredient.Stock,
+		Reason:       reason,
+		Note:         note,
+		OrderID:      orderID,
+		Actor:        actor,
+		CreatedAt:    time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if !wasLow && ingredient.IsLow() {
+		publishAfterCommit(tx, events.TopicInventory, events.InventoryLowStock, ingredient)
+	}
+	return &ingredient, nil
+}
+
+// Menu yang bahannya tidak cukup untuk satu porsi jadi sold out otomatis,
+// dan tersedia lagi setelah bahannya di-restock.
+func refreshStockAvailability(tx *gorm.DB) error {
+	short := tx.Table("recipe_items").
+		Select("recipe_items.menu_id").
+		Joins("JOIN ingredients ON ingredients.id = recipe_items.ingredient_id").
+		Where("ingredients.stock < recipe_items.quantity")
+
+	var soldOut []models.Menu
+	if err := tx.Where("is_available = ? AND id IN (?)", true, short).Find(&soldOut).Error; err != nil {
+		return err
+	}
+	if len(soldOut) > 0 {
+		ids := make([]uint, 0, len(soldOut))
+		for _, menu := range soldOut {
+			ids = append(ids, menu.ID)
+		}
+		if err := tx.Model(&models.Menu{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"is_available": false, "sold_out_by_stock": true}).Error; err != nil {
+			return err
+		}
+		for _, menu := range soldOut {
+			menu.IsAvailable = false
+			menu.SoldOutByStock = true
+			menu.AvailableNow = false
+			publishAfterCommit(tx, events.TopicInventory, events.MenuSoldOut, menu)
+		}
+	}
+
+	return tx.Model(&models.Menu{}).
+		Where("sold_out_by_stock = ? AND id NOT IN (?)", true, short).
+		Updates(map[string]interface{}{"is_available": true, "sold_out_by_stock": false}).Error
+}
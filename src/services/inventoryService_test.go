@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"titik-rindang/src/models"
+)
+
+func TestDeleteIngredientKeepsStockHistory(t *testing.T) {
+	db := openTestDB(t)
+	svc := NewInventoryService(db)
+
+	ingredient := models.Ingredient{Name: "Susu UHT", Unit: "ml", Stock: 1000}
+	if err := svc.CreateIngredient(&ingredient, "tester"); err != nil {
+		t.Fatalf("CreateIngredient: %v", err)
+	}
+	if _, err := svc.AdjustStock(ingredient.ID, -200, models.StockWaste, "basi", "tester"); err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+
+	if err := svc.DeleteIngredient(ingredient.ID); err != nil {
+		t.Fatalf("DeleteIngredient: %v", err)
+	}
+
+	if _, err := svc.GetIngredientByID(ingredient.ID); err == nil {
+		t.Error("deleted ingredient is still returned")
+	}
+	if n := countRows(t, db, &models.StockMovement{}); n != 2 {
+		t.Errorf("stock movements after delete = %d, want 2", n)
+	}
+
+	reused := models.Ingredient{Name: "Susu UHT", Unit: "ml"}
+	if err := svc.CreateIngredient(&reused, "tester"); err == nil {
+		t.Error("CreateIngredient reused the name of a deleted ingredient")
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"titik-rindang/src/events"
+	"titik-rindang/src/models"
+
+	"gorm.io/gorm"
+)
+
+type KitchenService struct {
+	DB *gorm.DB
+}
+
+func NewKitchenService(db *gorm.DB) *KitchenService {
+	return &KitchenService{DB: db}
+}
+
+// Satu baris di layar dapur/bar
+type KitchenTicket struct {
+	models.OrderItem
+	TableNo  int
+	Customer string
+	Options  string `gorm:"-"` // ringkasan modifier untuk layar dapur
+}
+
+// Status yang boleh dituju dari status sekarang
+var prepTransitions = map[string][]string{
+	models.PrepQueued:    {models.PrepPreparing, models.PrepVoided},
+	models.PrepPreparing: {models.PrepReady, models.PrepVoided},
+	models.PrepReady:     {models.PrepServed, models.PrepVoided},
+}
+
+// Antrian item yang belum diantar, yang paling lama di atas
+func (s *KitchenService) GetQueue(station string) ([]KitchenTicket, error) {
+	query := s.DB.Table("order_items").
+		Select("order_items.*, tables.table_no, orders.customer").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Joins("LEFT JOIN tables ON tables.id = orders.table_id").
+		Where("order_items.prep_status IN ?", []string{models.PrepQueued, models.PrepPreparing, models.PrepReady}).
+		Where("orders.status <> ?", models.OrderCancelled) // item order lama yang batal sebelum item ikut di-void
+
+	if station != "" {
+		query = query.Where("order_items.station = ?", station)
+	}
+
+	var tickets []KitchenTicket
+	if err := query.Order("order_items.created_at ASC, order_items.id ASC").Scan(&tickets).Error; err != nil {
+		return nil, err
+	}
+	for i := range tickets {
+		tickets[i].Options = tickets[i].Modifiers.Summary()
+	}
+	return tickets, nil
+}
+
+// Ubah status persiapan satu item. Void wajib pakai alasan.
+func (s *KitchenService) UpdateItemStatus(itemID uint, status string, reason string, actor string) (*models.OrderItem, error) {
+	var item models.OrderItem
+	if err := s.DB.First(&item, itemID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order item not found")
+		}
+		return nil, err
+	}
+
+	allowed := false
+	for _, next := range prepTransitions[item.PrepStatus] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, errors.New("cannot change item from " + item.PrepStatus + " to " + status)
+	}
+
+	// void ikut menghitung ulang total order
+	if status == models.PrepVoided {
+		if _, err := NewOrderService(s.DB).VoidItem(item.OrderID, item.ID, reason, actor); err != nil {
+			return nil, err
+		}
+		if err := s.DB.First(&item, itemID).Error; err != nil {
+			return nil, err
+		}
+		events.Publish(events.TopicKitchen, events.OrderItemStatus, item)
+		return &item, nil
+	}
+
+	now := time.Now()
+	switch status {
+	case models.PrepPreparing:
+		item.StartedAt = &now
+	case models.PrepReady:
+		item.ReadyAt = &now
+	case models.PrepServed:
+		item.ServedAt = &now
+	}
+	item.PrepStatus = status
+
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		if err := tx.Save(&item).Error; err != nil {
+			return err
+		}
+		return markOrderServedIfDone(tx, item.OrderID, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Publish(events.TopicKitchen, events.OrderItemStatus, item)
+	events.Publish(events.TopicOrder, events.OrderItemStatus, item)
+
+	return &item, nil
+}
+
+// Semua item sudah diantar (atau di-void) -> order otomatis jadi served
+func markOrderServedIfDone(tx *gorm.DB, orderID uint, actor string) error {
+	var pending int64
+	if err := tx.Model(&models.OrderItem{}).
+		Where("order_id = ? AND prep_status NOT IN ?", orderID, []string{models.PrepServed, models.PrepVoided}).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return nil
+	}
+
+	var served int64
+	if err := tx.Model(&models.OrderItem{}).
+		Where("order_id = ? AND prep_status = ?", orderID, models.PrepServed).
+		Count(&served).Error; err != nil {
+		return err
+	}
+	if served == 0 {
+		return nil
+	}
+
+	order, err := lockOrder(tx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != models.OrderOpen {
+		return nil
+	}
+	return transitionOrder(tx, order, models.OrderServed, actor, "all items served")
+}
@@ -0,0 +1,526 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"titik-rindang/src/helper"
+	"titik-rindang/src/models"
+	"titik-rindang/src/storage"
+
+	"gorm.io/gorm"
+)
+
+type MenuService struct {
+	DB      *gorm.DB
+	Storage storage.Storage
+}
+
+func NewMenuService(db *gorm.DB) *MenuService {
+	return &MenuService{DB: db, Storage: storage.Default()}
+}
+
+// Nama grup untuk menu yang belum punya kategori
+const UncategorizedName = "Lainnya"
+
+// Get All Menu, urut kategori lalu posisi di dalam kategori
+func (s *MenuService) GetAllMenu() ([]models.Menu, error) {
+	var menus []models.Menu
+	err := s.DB.
+		Preload("ModifierGroups.Options", orderByPosition).
+		Joins("LEFT JOIN menu_categories ON menu_categories.id = menus.category_id").
+		Order("menu_categories.position IS NULL, menu_categories.position ASC, menus.position ASC, menus.id ASC").
+		Find(&menus).Error
+	return menus, err
+}
+
+// Get Grouped Menu, menu tanpa kategori dikumpulkan di grup "Lainnya" paling bawah
+func (s *MenuService) GetGroupedMenu() ([]models.MenuCategory, error) {
+	var categories []models.MenuCategory
+	err := s.DB.
+		Preload("Menus", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC, id ASC") }).
+		Preload("Menus.ModifierGroups.Options", orderByPosition).
+		Order("position ASC, id ASC").
+		Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var uncategorized []models.Menu
+	if err := s.DB.Preload("ModifierGroups.Options", orderByPosition).Where("category_id IS NULL").Order("position ASC, id ASC").Find(&uncategorized).Error; err != nil {
+		return nil, err
+	}
+	if len(uncategorized) > 0 {
+		categories = append(categories, models.MenuCategory{Name: UncategorizedName, Menus: uncategorized})
+	}
+	return categories, nil
+}
+
+// Posisi berikutnya (paling bawah) di sebuah kategori
+func (s *MenuService) NextMenuPosition(categoryID *uint) (int, error) {
+	query := s.DB.Model(&models.Menu{})
+	if categoryID == nil {
+		query = query.Where("category_id IS NULL")
+	} else {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+
+	var last int
+	err := query.Select("COALESCE(MAX(position), 0)").Scan(&last).Error
+	return last + 1, err
+}
+
+// Pindah menu ke kategori lain, posisinya ditaruh paling bawah
+func (s *MenuService) AssignCategory(menu *models.Menu, categoryID *uint) error {
+	if categoryID != nil {
+		if _, err := s.GetCategoryByID(*categoryID); err != nil {
+			return err
+		}
+	}
+
+	position, err := s.NextMenuPosition(categoryID)
+	if err != nil {
+		return err
+	}
+	menu.CategoryID = categoryID
+	menu.Position = position
+	return nil
+}
+
+// Urutkan ulang menu di dalam satu kategori sesuai urutan ID yang dikirim
+func (s *MenuService) ReorderMenus(categoryID uint, menuIDs []uint) error {
+	if _, err := s.GetCategoryByID(categoryID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.DB.Model(&models.Menu{}).Where("category_id = ? AND id IN ?", categoryID, menuIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(menuIDs) {
+		return errors.New("some menus do not belong to this category")
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range menuIDs {
+			if err := tx.Model(&models.Menu{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Get All Categories
+func (s *MenuService) GetAllCategories() ([]models.MenuCategory, error) {
+	var categories []models.MenuCategory
+	err := s.DB.Order("position ASC, id ASC").Find(&categories).Error
+	return categories, err
+}
+
+// Get Category by ID
+func (s *MenuService) GetCategoryByID(id uint) (*models.MenuCategory, error) {
+	var category models.MenuCategory
+	if err := s.DB.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("menu category not found")
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Create Category, tanpa posisi berarti paling bawah
+func (s *MenuService) CreateCategory(category *models.MenuCategory) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return errors.New("category name is required")
+	}
+
+	if category.Position <= 0 {
+		var last int
+		if err := s.DB.Model(&models.MenuCategory{}).Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		category.Position = last + 1
+	}
+	return s.DB.Create(category).Error
+}
+
+// Update Category
+func (s *MenuService) UpdateCategory(id uint, name string, position int) (*models.MenuCategory, error) {
+	category, err := s.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name = strings.TrimSpace(name); name != "" {
+		category.Name = name
+	}
+	if position > 0 {
+		category.Position = position
+	}
+
+	if err := s.DB.Save(category).Error; err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// Urutkan ulang semua kategori sesuai urutan ID yang dikirim
+func (s *MenuService) ReorderCategories(categoryIDs []uint) error {
+	var count int64
+	if err := s.DB.Model(&models.MenuCategory{}).Where("id IN ?", categoryIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(categoryIDs) {
+		return errors.New("menu category not found")
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range categoryIDs {
+			if err := tx.Model(&models.MenuCategory{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete Category, menu di dalamnya jadi tanpa kategori
+func (s *MenuService) DeleteCategory(id uint) error {
+	if _, err := s.GetCategoryByID(id); err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Menu{}).Where("category_id = ?", id).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.MenuCategory{}, id).Error
+	})
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// Get Menu by ID (lengkap dengan kategori & modifier)
+func (s *MenuService) GetMenuByID(id uint) (*models.Menu, error) {
+	var menu models.Menu
+	err := s.DB.
+		Preload("Category").
+		Preload("ModifierGroups.Options", orderByPosition).
+		First(&menu, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("menu not found")
+		}
+		return nil, err
+	}
+	return &menu, nil
+}
+
+// Get All Modifier Groups
+func (s *MenuService) GetAllModifierGroups() ([]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	err := s.DB.Preload("Options", orderByPosition).Order("id ASC").Find(&groups).Error
+	return groups, err
+}
+
+func (s *MenuService) GetModifierGroupByID(id uint) (*models.ModifierGroup, error) {
+	var group models.ModifierGroup
+	if err := s.DB.Preload("Options", orderByPosition).First(&group, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("modifier group not found")
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+func validateModifierGroup(group *models.ModifierGroup) error {
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		return errors.New("modifier group name is required")
+	}
+	if len(group.Options) == 0 {
+		return errors.New("modifier group must have at least one option")
+	}
+	if group.MinSelect < 0 || group.MaxSelect < 0 {
+		return errors.New("min and max selections must not be negative")
+	}
+	if group.MaxSelect > 0 && group.MaxSelect < group.MinRequired() {
+		return errors.New("max selections must not be less than min selections")
+	}
+	if group.MinRequired() > len(group.Options) {
+		return errors.New("min selections exceed the number of options")
+	}
+
+	for i := range group.Options {
+		group.Options[i].Name = strings.TrimSpace(group.Options[i].Name)
+		if group.Options[i].Name == "" {
+			return errors.New("option name is required")
+		}
+		if group.Options[i].PriceDelta < 0 {
+			return errors.New("option price must not be negative")
+		}
+		group.Options[i].Position = i + 1
+	}
+	return nil
+}
+
+// Create Modifier Group beserta opsinya
+func (s *MenuService) CreateModifierGroup(group *models.ModifierGroup) error {
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+	return s.DB.Create(group).Error
+}
+
+// Update Modifier Group, daftar opsi diganti seluruhnya.
+// Order lama aman karena item menyimpan snapshot modifier.
+func (s *MenuService) UpdateModifierGroup(id uint, updated *models.ModifierGroup) (*models.ModifierGroup, error) {
+	group, err := s.GetModifierGroupByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Name = updated.Name
+	group.Required = updated.Required
+	group.MinSelect = updated.MinSelect
+	group.MaxSelect = updated.MaxSelect
+	group.Options = updated.Options
+	for i := range group.Options {
+		group.Options[i].ID = 0
+		group.Options[i].GroupID = group.ID
+	}
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Options").Save(group).Error; err != nil {
+			return err
+		}
+		return tx.Create(&group.Options).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Delete Modifier Group, otomatis lepas dari semua menu
+func (s *MenuService) DeleteModifierGroup(id uint) error {
+	group, err := s.GetModifierGroupByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM menu_modifier_groups WHERE modifier_group_id = ?", group.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ModifierGroup{}, group.ID).Error
+	})
+}
+
+var ErrMenuInUse = errors.New("menu has been ordered before, mark it unavailable instead")
+
+// Delete Menu beserta resep & relasi grup modifiernya dalam satu transaksi.
+// Menu yang pernah dipesan tidak bisa dihapus (item order masih menunjuk ke menu ini).
+func (s *MenuService) DeleteMenu(id uint) (*models.Menu, error) {
+	var menu models.Menu
+	if err := s.DB.First(&menu, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("menu not found")
+		}
+		return nil, err
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var ordered int64
+		if err := tx.Model(&models.OrderItem{}).Where("menu_id = ?", menu.ID).Count(&ordered).Error; err != nil {
+			return err
+		}
+		if ordered > 0 {
+			return ErrMenuInUse
+		}
+
+		if err := tx.Where("menu_id = ?", menu.ID).Delete(&models.RecipeItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&menu).Association("ModifierGroups").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&menu).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &menu, nil
+}
+
+// Pasang grup modifier ke menu (menggantikan yang lama)
+func (s *MenuService) SetMenuModifierGroups(menuID uint, groupIDs []uint) (*models.Menu, error) {
+	menu, err := s.GetMenuByID(menuID)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []models.ModifierGroup
+	if len(groupIDs) > 0 {
+		if err := s.DB.Where("id IN ?", groupIDs).Find(&groups).Error; err != nil {
+			return nil, err
+		}
+		if len(groups) != len(groupIDs) {
+			return nil, errors.New("modifier group not found")
+		}
+	}
+
+	if err := s.DB.Model(menu).Association("ModifierGroups").Replace(groups); err != nil {
+		return nil, err
+	}
+	return s.GetMenuByID(menuID)
+}
+
+// Cocokkan opsi yang dipilih dengan aturan grup di menu, hasilnya snapshot untuk order item
+func ResolveModifiers(menu models.Menu, optionIDs []uint) (models.ItemModifiers, error) {
+	chosen := map[uint]bool{}
+	for _, id := range optionIDs {
+		if chosen[id] {
+			return nil, fmt.Errorf("option %d is selected more than once", id)
+		}
+		chosen[id] = true
+	}
+
+	modifiers := models.ItemModifiers{}
+	matched := 0
+	for _, group := range menu.ModifierGroups {
+		selected := 0
+		for _, option := range group.Options {
+			if !chosen[option.ID] {
+				continue
+			}
+			selected++
+			modifiers = append(modifiers, models.ItemModifier{
+				Group:      group.Name,
+				Option:     option.Name,
+				PriceDelta: option.PriceDelta,
+			})
+		}
+		matched += selected
+
+		if selected < group.MinRequired() {
+			return nil, fmt.Errorf("%s: choose at least %d option(s) for %s", menu.Name, group.MinRequired(), group.Name)
+		}
+		if group.MaxSelect > 0 && selected > group.MaxSelect {
+			return nil, fmt.Errorf("%s: choose at most %d option(s) for %s", menu.Name, group.MaxSelect, group.Name)
+		}
+	}
+
+	if matched != len(optionIDs) {
+		return nil, fmt.Errorf("some options are not available for %s", menu.Name)
+	}
+	return modifiers, nil
+}
+
+// Ubah status tersedia / sold out dan jam tersedia menu. Field nil tidak diubah.
+func (s *MenuService) SetAvailability(id uint, isAvailable *bool, from, until *string) (*models.Menu, error) {
+	menu, err := s.GetMenuByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAvailable != nil {
+		menu.IsAvailable = *isAvailable
+		menu.SoldOutByStock = false // diatur manual, bukan lagi otomatis dari stok
+	}
+	if from != nil {
+		if err := validateClock(*from); err != nil {
+			return nil, err
+		}
+		menu.AvailableFrom = *from
+	}
+	if until != nil {
+		if err := validateClock(*until); err != nil {
+			return nil, err
+		}
+		menu.AvailableUntil = *until
+	}
+	if menu.AvailableFrom != "" && menu.AvailableFrom == menu.AvailableUntil {
+		return nil, errors.New("available_from and available_until must differ")
+	}
+
+	err = s.DB.Model(&models.Menu{}).Where("id = ?", menu.ID).Updates(map[string]interface{}{
+		"is_available":      menu.IsAvailable,
+		"sold_out_by_stock": menu.SoldOutByStock,
+		"available_from":    menu.AvailableFrom,
+		"available_until":   menu.AvailableUntil,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return s.GetMenuByID(id)
+}
+
+// Jam format HH:MM, kosong berarti tanpa batas
+func validateClock(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := time.Parse("15:04", value); err != nil || len(value) != 5 {
+		return errors.New("time must use HH:MM format, e.g. 07:00")
+	}
+	return nil
+}
+
+const menuImageFolder = "menu/"
+
+// Validasi & resize gambar upload, simpan semua varian dengan nama acak
+func (s *MenuService) SaveImage(r io.Reader) (models.MenuImages, error) {
+	variants, err := helper.ProcessImage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := helper.RandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+
+	images := models.MenuImages{}
+	for variant, data := range variants {
+		key := menuImageFolder + name + "_" + variant + ".jpg"
+		if err := s.Storage.Put(key, bytes.NewReader(data), "image/jpeg"); err != nil {
+			s.DeleteImages(images)
+			return nil, errors.New("failed to save image")
+		}
+		images[variant] = s.Storage.URL(key)
+	}
+	return images, nil
+}
+
+// Hapus semua file varian gambar menu, URL di luar storage (link eksternal) dilewati
+func (s *MenuService) DeleteImages(images models.MenuImages) {
+	removed := map[string]bool{}
+	for _, url := range images {
+		key, ok := storage.KeyFromURL(s.Storage, url)
+		if !ok || removed[key] || !strings.HasPrefix(key, menuImageFolder) {
+			continue
+		}
+		removed[key] = true
+		_ = s.Storage.Delete(key)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"titik-rindang/src/models"
+)
+
+func TestCreateUnlimitedModifierGroup(t *testing.T) {
+	db := openTestDB(t)
+	svc := NewMenuService(db)
+
+	group := models.ModifierGroup{
+		Name:      "Add-on",
+		MaxSelect: 0, // tanpa batas
+		Options:   []models.ModifierOption{{Name: "Extra Shot", PriceDelta: 5000}, {Name: "Oat Milk", PriceDelta: 7000}},
+	}
+	if err := svc.CreateModifierGroup(&group); err != nil {
+		t.Fatalf("CreateModifierGroup: %v", err)
+	}
+	if group.MaxSelect != 0 {
+		t.Errorf("MaxSelect after create = %d, want 0", group.MaxSelect)
+	}
+
+	stored, err := svc.GetModifierGroupByID(group.ID)
+	if err != nil {
+		t.Fatalf("GetModifierGroupByID: %v", err)
+	}
+	if stored.MaxSelect != 0 {
+		t.Errorf("stored MaxSelect = %d, want 0 (unlimited)", stored.MaxSelect)
+	}
+
+	menu := createTestMenu(t, db, "Kopi Susu", 18000)
+	if _, err := svc.SetMenuModifierGroups(menu.ID, []uint{group.ID}); err != nil {
+		t.Fatalf("SetMenuModifierGroups: %v", err)
+	}
+	full, err := svc.GetMenuByID(menu.ID)
+	if err != nil {
+		t.Fatalf("GetMenuByID: %v", err)
+	}
+	optionIDs := []uint{full.ModifierGroups[0].Options[0].ID, full.ModifierGroups[0].Options[1].ID}
+	if _, err := ResolveModifiers(*full, optionIDs); err != nil {
+		t.Errorf("choosing every option of an unlimited group: %v", err)
+	}
+}
+
+func TestDeleteMenuDetachesModifierGroups(t *testing.T) {
+	db := openTestDB(t)
+	svc := NewMenuService(db)
+
+	group := models.ModifierGroup{Name: "Size", MaxSelect: 1, Options: []models.ModifierOption{{Name: "Large", PriceDelta: 5000}}}
+	if err := svc.CreateModifierGroup(&group); err != nil {
+		t.Fatalf("CreateModifierGroup: %v", err)
+	}
+	menu := createTestMenu(t, db, "Kopi Susu", 18000)
+	if _, err := svc.SetMenuModifierGroups(menu.ID, []uint{group.ID}); err != nil {
+		t.Fatalf("SetMenuModifierGroups: %v", err)
+	}
+
+	if _, err := svc.DeleteMenu(menu.ID); err != nil {
+		t.Fatalf("DeleteMenu: %v", err)
+	}
+
+	var links int64
+	db.Table("menu_modifier_groups").Where("menu_id = ?", menu.ID).Count(&links)
+	if links != 0 {
+		t.Errorf("menu_modifier_groups rows = %d, want 0", links)
+	}
+	if _, err := svc.GetModifierGroupByID(group.ID); err != nil {
+		t.Errorf("modifier group removed with the menu: %v", err)
+	}
+}
+
+func TestDeleteOrderedMenuKeepsRecipe(t *testing.T) {
+	db := openTestDB(t)
+	svc := NewMenuService(db)
+
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Kopi Susu", 18000)
+	ingredient := models.Ingredient{Name: "Susu", Unit: "ml", Stock: 1000}
+	if err := db.Create(&ingredient).Error; err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	if err := db.Create(&models.RecipeItem{MenuID: menu.ID, IngredientID: ingredient.ID, Quantity: 150}).Error; err != nil {
+		t.Fatalf("create recipe: %v", err)
+	}
+	if _, err := NewOrderService(db).CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 1}}, "tester"); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+
+	if _, err := svc.DeleteMenu(menu.ID); !errors.Is(err, ErrMenuInUse) {
+		t.Fatalf("DeleteMenu error = %v, want ErrMenuInUse", err)
+	}
+	if n := countRows(t, db, &models.RecipeItem{}); n != 1 {
+		t.Errorf("recipe rows = %d, want 1 (delete must roll back)", n)
+	}
+
+	unused := createTestMenu(t, db, "Es Teh", 8000)
+	if err := db.Create(&models.RecipeItem{MenuID: unused.ID, IngredientID: ingredient.ID, Quantity: 50}).Error; err != nil {
+		t.Fatalf("create recipe: %v", err)
+	}
+	if _, err := svc.DeleteMenu(unused.ID); err != nil {
+		t.Fatalf("DeleteMenu: %v", err)
+	}
+	if n := countRows(t, db, &models.RecipeItem{}); n != 1 {
+		t.Errorf("recipe rows = %d, want only the ordered menu's recipe left", n)
+	}
+}
+
+func TestUnavailableMenuStaysUnavailable(t *testing.T) {
+	db := openTestDB(t)
+	svc := NewMenuService(db)
+
+	menu := models.Menu{Name: "Kopi Musiman", Price: 25000, Station: "bar", IsAvailable: false}
+	if err := db.Create(&menu).Error; err != nil {
+		t.Fatalf("create menu: %v", err)
+	}
+	if menu.IsAvailable {
+		t.Error("IsAvailable after create = true, want false")
+	}
+
+	stored, err := svc.GetMenuByID(menu.ID)
+	if err != nil {
+		t.Fatalf("GetMenuByID: %v", err)
+	}
+	if stored.IsAvailable || stored.AvailableNow {
+		t.Errorf("stored IsAvailable/AvailableNow = %v/%v, want false/false", stored.IsAvailable, stored.AvailableNow)
+	}
+}
@@ -2,11 +2,16 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"titik-rindang/src/events"
+	"titik-rindang/src/helper"
 	"titik-rindang/src/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderService struct {
@@ -18,19 +23,21 @@ func NewOrderService(db *gorm.DB) *OrderService {
 }
 
 type OrderItemInput struct {
-	MenuID uint
-	Qty    int
+	MenuID    uint
+	Qty       int
+	OptionIDs []uint // opsi modifier yang dipilih
 }
 
 // 🔹 Create Order 
-func (s *OrderService) CreateOrder(tableID uint, customer string, items []OrderItemInput) (*models.Order, error) {
+func (s *OrderService) CreateOrder(tableID uint, customer string, items []OrderItemInput, actor string) (*models.Order, error) {
 	var table models.Table
 	if err := s.DB.First(&table, tableID).Error; err != nil {
 		return nil, errors.New("table not found")
 	}
 
 	var upcoming models.Reservation
-	err := s.DB.Where("table_id = ? AND reservation_date > ?", tableID, time.Now()).
+	err := activeReservations(s.DB).
+		Where("table_id = ? AND reservation_date > ?", tableID, time.Now()).
 		Order("reservation_date ASC").
 		First(&upcoming).Error
 
@@ -43,70 +50,800 @@ func (s *OrderService) CreateOrder(tableID uint, customer string, items []OrderI
 		}
 	}
 
+	trackingToken, err := helper.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
 	order := models.Order{
-		TableID:   tableID,
-		Customer:  customer,
-		Status:    "unpaid",
-		CreatedAt: time.Now(),
+		TableID:       tableID,
+		Customer:      customer,
+		TrackingToken: trackingToken,
+		Status:        models.OrderOpen,
+		CreatedAt:     time.Now(),
+	}
+
+	// order, item, total & status meja harus berhasil semua atau batal semua
+	err = transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+
+		subtotal := models.Money(0)
+		var orderItems []models.OrderItem
+
+		for _, item := range items {
+			orderItem, err := buildOrderItem(tx, order.ID, item)
+			if err != nil {
+				return err
+			}
+			subtotal += orderItem.Subtotal
+			orderItems = append(orderItems, orderItem)
+		}
+
+		if err := tx.Create(&orderItems).Error; err != nil {
+			return err
+		}
+
+		applyOrderCharges(&order, helper.CalculateOrderCharges(subtotal))
+		order.UpdatedAt = time.Now()
+		if err := tx.Save(&order).Error; err != nil {
+			return err
+		}
+
+		if err := recordOrderStatus(tx, order.ID, "", models.OrderOpen, actor, ""); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Table{}).Where("id = ?", tableID).Update("status", "in_use").Error
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.DB.Create(&order).Error; err != nil {
+	var fullOrder models.Order
+	if err := s.DB.Preload("OrderItems.Menu").Preload("Table").First(&fullOrder, order.ID).Error; err != nil {
 		return nil, err
 	}
 
-	total := float64(0)
-	var orderItems []models.OrderItem
+	events.Publish(events.TopicOrder, events.OrderCreated, fullOrder)
+	events.Publish(events.TopicKitchen, events.OrderCreated, fullOrder)
+	events.Publish(events.TopicTable, events.TableStatusChanged, fullOrder.Table)
 
-	for _, item := range items {
-		var menu models.Menu
-		if err := s.DB.First(&menu, item.MenuID).Error; err != nil {
-			return nil, errors.New("menu not found")
+	return &fullOrder, nil
+}
+
+// Item baru dengan snapshot nama, harga & modifier menu saat ini
+func buildOrderItem(tx *gorm.DB, orderID uint, input OrderItemInput) (models.OrderItem, error) {
+	if input.Qty <= 0 {
+		return models.OrderItem{}, fmt.Errorf("invalid quantity for menu %d", input.MenuID)
+	}
+
+	var menu models.Menu
+	if err := tx.Preload("ModifierGroups.Options").First(&menu, input.MenuID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.OrderItem{}, fmt.Errorf("menu %d not found", input.MenuID)
+		}
+		return models.OrderItem{}, err
+	}
+
+	if reason := menu.UnavailableReason(time.Now()); reason != "" {
+		return models.OrderItem{}, fmt.Errorf("%s is %s", menu.Name, reason)
+	}
+
+	modifiers, err := ResolveModifiers(menu, input.OptionIDs)
+	if err != nil {
+		return models.OrderItem{}, err
+	}
+
+	item := models.OrderItem{
+		OrderID:   orderID,
+		MenuID:    input.MenuID,
+		MenuName:  menu.Name,
+		UnitPrice: menu.Price,
+		Modifiers: modifiers,
+		Quantity:  input.Qty,
+		Station:   menu.Station,
+	}
+	item.Subtotal = item.LinePrice().Times(item.Quantity)
+	return item, nil
+}
+
+// 🔹 Add Items (ronde kedua, order masih belum dibayar)
+func (s *OrderService) AddItems(id uint, items []OrderItemInput, actor string) (*models.Order, error) {
+	if len(items) == 0 {
+		return nil, errors.New("order must have at least one item")
+	}
+
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		order, err := lockEditableOrder(tx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, input := range items {
+			item, err := buildOrderItem(tx, order.ID, input)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+			if err := recordItemChange(tx, item, models.ItemAdded, 0, item.Quantity, "", actor); err != nil {
+				return err
+			}
+		}
+
+		// order yang sudah served dibuka lagi supaya item baru diantar dulu
+		if order.Status == models.OrderServed {
+			if err := transitionOrder(tx, order, models.OrderOpen, actor, "items added"); err != nil {
+				return err
+			}
+		}
+		return recalculateOrder(tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.publishOrderUpdate(id)
+}
+
+// 🔹 Change Item Quantity (hanya item yang belum diproses dapur)
+func (s *OrderService) ChangeItemQuantity(id uint, itemID uint, qty int, actor string) (*models.Order, error) {
+	if qty <= 0 {
+		return nil, errors.New("quantity must be at least 1, void the item instead")
+	}
+
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		order, err := lockEditableOrder(tx, id)
+		if err != nil {
+			return err
+		}
+
+		item, err := findUnpaidItem(tx, order.ID, itemID)
+		if err != nil {
+			return err
+		}
+		if item.PrepStatus != models.PrepQueued {
+			return errors.New("item is already " + item.PrepStatus + ", add a new item instead")
+		}
+		if item.Quantity == qty {
+			return nil
+		}
+
+		from := item.Quantity
+		item.Quantity = qty
+		item.Subtotal = item.LinePrice().Times(qty)
+		if err := tx.Save(item).Error; err != nil {
+			return err
+		}
+		if err := recordItemChange(tx, *item, models.ItemQuantityChanged, from, qty, "", actor); err != nil {
+			return err
+		}
+		return recalculateOrder(tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.publishOrderUpdate(id)
+}
+
+// 🔹 Void Item (alasan wajib diisi)
+func (s *OrderService) VoidItem(id uint, itemID uint, reason string, actor string) (*models.Order, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("void reason is required")
+	}
+
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		order, err := lockEditableOrder(tx, id)
+		if err != nil {
+			return err
+		}
+
+		item, err := findUnpaidItem(tx, order.ID, itemID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		item.PrepStatus = models.PrepVoided
+		item.VoidedAt = &now
+		item.VoidReason = reason
+		if err := tx.Save(item).Error; err != nil {
+			return err
+		}
+		if err := recordItemChange(tx, *item, models.ItemVoided, item.Quantity, 0, reason, actor); err != nil {
+			return err
 		}
+		if err := recalculateOrder(tx, order); err != nil {
+			return err
+		}
+		return markOrderServedIfDone(tx, order.ID, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.publishOrderUpdate(id)
+}
+
+func (s *OrderService) publishOrderUpdate(id uint) (*models.Order, error) {
+	fullOrder, err := s.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
 
-		subtotal := float64(item.Qty) * menu.Price
-		total += subtotal
+	events.Publish(events.TopicOrder, events.OrderItemsChanged, fullOrder)
+	events.Publish(events.TopicKitchen, events.OrderItemsChanged, fullOrder)
+	return fullOrder, nil
+}
+
+// Item hanya boleh diubah selama order belum dibayar
+func lockEditableOrder(tx *gorm.DB, id uint) (*models.Order, error) {
+	order, err := lockOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderOpen && order.Status != models.OrderServed {
+		return nil, fmt.Errorf("order items cannot be changed while %s", order.Status)
+	}
+	return order, nil
+}
 
-		orderItems = append(orderItems, models.OrderItem{
-			OrderID:  order.ID,
-			MenuID:   item.MenuID,
-			Quantity: item.Qty,
-			Subtotal: subtotal,
-		})
+func findUnpaidItem(tx *gorm.DB, orderID uint, itemID uint) (*models.OrderItem, error) {
+	var item models.OrderItem
+	if err := tx.Where("order_id = ? AND id = ?", orderID, itemID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order item not found")
+		}
+		return nil, err
+	}
+	if item.PrepStatus == models.PrepVoided {
+		return nil, errors.New("order item is already voided")
 	}
 
-	if err := s.DB.Create(&orderItems).Error; err != nil {
+	var paid int64
+	if err := tx.Model(&models.PaymentItem{}).Where("order_item_id = ?", item.ID).Count(&paid).Error; err != nil {
 		return nil, err
 	}
+	if paid > 0 {
+		return nil, errors.New("order item is already paid")
+	}
+	return &item, nil
+}
+
+// Hitung ulang subtotal dari item yang tidak di-void, tarif service & PB1 tetap
+// memakai tarif saat order dibuat.
+func recalculateOrder(tx *gorm.DB, order *models.Order) error {
+	var subtotal models.Money
+	if err := tx.Model(&models.OrderItem{}).
+		Where("order_id = ? AND prep_status <> ?", order.ID, models.PrepVoided).
+		Select("COALESCE(SUM(subtotal), 0)").
+		Scan(&subtotal).Error; err != nil {
+		return err
+	}
+
+	paid, err := totalPaid(tx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	cfg := helper.GetPricingConfig()
+	cfg.ServiceRate = order.ServiceRate
+	cfg.TaxRate = order.TaxRate
+	charges := helper.ApplyCharges(subtotal, cfg)
+	if charges.Total < paid {
+		return fmt.Errorf("order total %s would fall below the %s already paid", charges.Total, paid)
+	}
 
-	order.Total = total
+	applyOrderCharges(order, charges)
 	order.UpdatedAt = time.Now()
-	s.DB.Save(&order)
+	return tx.Save(order).Error
+}
 
-	s.DB.Model(&models.Table{}).Where("id = ?", tableID).Update("status", "in_use")
+func recordItemChange(tx *gorm.DB, item models.OrderItem, action string, fromQty, toQty int, reason, actor string) error {
+	return tx.Create(&models.OrderItemChange{
+		OrderID:     item.OrderID,
+		OrderItemID: item.ID,
+		MenuName:    item.MenuName,
+		Action:      action,
+		FromQty:     fromQty,
+		ToQty:       toQty,
+		Reason:      reason,
+		Actor:       actor,
+		CreatedAt:   time.Now(),
+	}).Error
+}
 
-	var fullOrder models.Order
-	s.DB.Preload("OrderItems.Menu").Preload("Table").First(&fullOrder, order.ID)
+// Satu pembayaran masuk. Tanpa Amount/ItemIDs/Shares berarti lunasi sisa tagihan.
+type PaymentInput struct {
+	Amount          models.Money
+	Method          string
+	ItemIDs         []uint // split per item
+	Shares          int    // split rata ke N orang
+	PaymentIntentID *uint
+}
 
-	return &fullOrder, nil
+// 🔹 Confirm Order (bayar penuh sisa tagihan)
+func (s *OrderService) ConfirmOrder(id uint, paymentMethod string, actor string) (*models.Order, error) {
+	return s.AddPayment(id, PaymentInput{Method: paymentMethod}, actor)
+}
+
+// 🔹 Add Payment (split bill / pembayaran sebagian)
+func (s *OrderService) AddPayment(id uint, input PaymentInput, actor string) (*models.Order, error) {
+	if input.Method == "" {
+		return nil, errors.New("payment method is required")
+	}
+
+	var fullOrder *models.Order
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		fullyPaid, err := addPayment(tx, id, input, actor)
+		if err != nil {
+			return err
+		}
+
+		fullOrder, err = NewOrderService(tx).GetOrder(id)
+		if err != nil {
+			return err
+		}
+
+		// dikirim setelah commit, juga kalau dipanggil dari transaksi webhook
+		if fullyPaid {
+			publishAfterCommit(tx, events.TopicOrder, events.OrderPaid, fullOrder)
+			publishAfterCommit(tx, events.TopicTable, events.TableStatusChanged, fullOrder.Table)
+		} else {
+			publishAfterCommit(tx, events.TopicOrder, events.OrderPaymentAdded, fullOrder)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fullOrder, nil
 }
 
-// 🔹 Confirm Order
-func (s *OrderService) ConfirmOrder(id uint, paymentMethod string) (*models.Order, error) {
+// 🔹 Sisa tagihan order
+func (s *OrderService) Balance(id uint) (models.Money, error) {
 	var order models.Order
 	if err := s.DB.First(&order, id).Error; err != nil {
-		return nil, errors.New("order not found")
+		return 0, errors.New("order not found")
 	}
 
-	order.Status = "paid"
-	order.PaymentMethod = paymentMethod
-	order.UpdatedAt = time.Now()
+	paid, err := totalPaid(s.DB, id)
+	if err != nil {
+		return 0, err
+	}
+	return order.Total + order.Rounding - paid, nil
+}
+
+func totalPaid(tx *gorm.DB, orderID uint) (models.Money, error) {
+	var paid models.Money
+	err := tx.Model(&models.Payment{}).
+		Where("order_id = ?", orderID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&paid).Error
+	return paid, err
+}
+
+// Catat payment & tandai order paid kalau tagihan sudah tertutup.
+// Return true kalau order jadi lunas.
+func addPayment(tx *gorm.DB, id uint, input PaymentInput, actor string) (bool, error) {
+	order, err := lockOrder(tx, id)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != models.OrderServed {
+		return false, fmt.Errorf("order cannot be paid while %s", order.Status)
+	}
+
+	paid, err := totalPaid(tx, id)
+	if err != nil {
+		return false, err
+	}
+	remaining := order.Total - paid
+	if remaining <= 0 {
+		return false, errors.New("order is already fully paid")
+	}
+
+	payment := models.Payment{
+		OrderID:         id,
+		Method:          input.Method,
+		PaymentIntentID: input.PaymentIntentID,
+		ReceivedBy:      actor,
+		CreatedAt:       time.Now(),
+	}
+
+	switch {
+	case len(input.ItemIDs) > 0:
+		var items []models.OrderItem
+		if err := tx.Where("order_id = ? AND id IN ? AND prep_status <> ?", id, input.ItemIDs, models.PrepVoided).
+			Find(&items).Error; err != nil {
+			return false, err
+		}
+		if len(items) != len(input.ItemIDs) {
+			return false, errors.New("some items do not belong to this order")
+		}
+
+		var alreadyPaid int64
+		if err := tx.Model(&models.PaymentItem{}).Where("order_item_id IN ?", input.ItemIDs).Count(&alreadyPaid).Error; err != nil {
+			return false, err
+		}
+		if alreadyPaid > 0 {
+			return false, errors.New("some items are already paid")
+		}
+
+		// service & pajak ikut dibagi sesuai porsi item
+		ratio := 100.0
+		if order.Subtotal > 0 {
+			ratio = float64(order.Total) / float64(order.Subtotal) * 100
+		}
+		for _, item := range items {
+			share := item.Subtotal.Percent(ratio)
+			payment.Amount += share
+			payment.Items = append(payment.Items, models.PaymentItem{OrderItemID: item.ID, Amount: share})
+		}
+
+		// item terakhir yang belum dibayar ikut menutup selisih pembulatan
+		var unpaidItems int64
+		if err := tx.Model(&models.OrderItem{}).
+			Where("order_id = ? AND prep_status <> ?", id, models.PrepVoided).
+			Where("id NOT IN (?)", tx.Model(&models.PaymentItem{}).Select("order_item_id")).
+			Count(&unpaidItems).Error; err != nil {
+			return false, err
+		}
+		if unpaidItems == int64(len(items)) {
+			payment.Amount = remaining
+		}
+		payment.SplitType = "items"
+
+	case input.Shares > 1:
+		payment.Amount = order.Total.SplitCeil(input.Shares)
+		if payment.Amount > remaining {
+			payment.Amount = remaining // orang terakhir bayar sisanya
+		}
+		payment.SplitType = "equal"
+		payment.Shares = input.Shares
+
+	case input.Amount > 0:
+		payment.Amount = input.Amount
+		payment.SplitType = "amount"
+
+	default:
+		payment.Amount = remaining
+		payment.SplitType = "full"
+	}
+
+	// pelunasan tunai dibulatkan ke kelipatan CASH_ROUNDING, selisihnya dicatat di order
+	closing := payment.Amount == remaining
+	if strings.EqualFold(input.Method, models.PaymentCash) {
+		due := helper.CashDue(remaining, helper.GetPricingConfig().RoundingUnit)
+		if payment.Amount == remaining || payment.Amount == due {
+			payment.Amount = due
+			order.Rounding = due - remaining
+			closing = true
+		}
+	}
+
+	if !closing && payment.Amount > remaining {
+		return false, fmt.Errorf("payment %s exceeds remaining balance %s", payment.Amount, remaining)
+	}
+
+	if err := tx.Create(&payment).Error; err != nil {
+		return false, err
+	}
+
+	if !closing {
+		return false, nil
+	}
+
+	// metode campuran ditulis "split"
+	var methods int64
+	if err := tx.Model(&models.Payment{}).Where("order_id = ?", id).Distinct("method").Count(&methods).Error; err != nil {
+		return false, err
+	}
+	order.PaymentMethod = input.Method
+	if methods > 1 {
+		order.PaymentMethod = "split"
+	}
+
+	if err := transitionOrder(tx, order, models.OrderPaid, actor, "paid in full"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+var (
+	ErrOrderItemsNotReady = errors.New("order cannot be served while some items are still queued or being prepared")
+	ErrOrderHasPayments   = errors.New("order already has payments, it cannot be cancelled")
+)
+
+// 🔹 Change Order Status (served, cancelled, refunded)
+func (s *OrderService) ChangeStatus(id uint, status string, actor string, note string) (*models.Order, error) {
+	if status == models.OrderPaid {
+		return nil, errors.New("use the confirm endpoint to mark an order as paid")
+	}
+
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
 
-	if err := s.DB.Save(&order).Error; err != nil {
+		return transitionOrder(tx, order, status, actor, note)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	var fullOrder models.Order
-	s.DB.Preload("OrderItems.Menu").Preload("Table").First(&fullOrder, order.ID)
+	fullOrder, err := s.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
 
-	return &fullOrder, nil
+	events.Publish(events.TopicOrder, events.OrderStatusChanged, fullOrder)
+	if status == models.OrderCancelled {
+		events.Publish(events.TopicKitchen, events.OrderStatusChanged, fullOrder)
+		events.Publish(events.TopicTable, events.TableStatusChanged, fullOrder.Table)
+	}
+
+	return fullOrder, nil
+}
+
+var ErrOrderNotDeletable = errors.New("only open orders that were never served or paid can be deleted, cancel the order instead")
+
+// 🔹 Delete Order (salah input). Order yang sudah pernah diantar / dibayar harus di-cancel
+// supaya jejak pembayaran & stoknya tetap ada.
+func (s *OrderService) DeleteOrder(id uint) error {
+	var table models.Table
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.OrderOpen {
+			return ErrOrderNotDeletable
+		}
+
+		var history, payments, intents int64
+		if err := tx.Model(&models.OrderStatusHistory{}).Where("order_id = ? AND to_status <> ?", id, models.OrderOpen).Count(&history).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Payment{}).Where("order_id = ?", id).Count(&payments).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.PaymentIntent{}).Where("order_id = ?", id).Count(&intents).Error; err != nil {
+			return err
+		}
+		if history > 0 || payments > 0 || intents > 0 {
+			return ErrOrderNotDeletable
+		}
+
+		for _, model := range []interface{}{&models.OrderItemChange{}, &models.OrderStatusHistory{}, &models.OrderItem{}} {
+			if err := tx.Where("order_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(order).Error; err != nil {
+			return err
+		}
+
+		if err := releaseTableIfIdle(tx, order.TableID); err != nil {
+			return err
+		}
+		return tx.First(&table, order.TableID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	events.Publish(events.TopicTable, events.TableStatusChanged, table)
+	return nil
+}
+
+// 🔹 Get Order (lengkap dengan item, meja & riwayat status)
+func (s *OrderService) GetOrder(id uint) (*models.Order, error) {
+	var order models.Order
+	err := s.DB.
+		Preload("OrderItems.Menu").
+		Preload("Table").
+		Preload("StatusHistory", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("ItemChanges", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Payments.Items").
+		First(&order, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// 🔹 Track Order (public, pakai tracking token)
+func (s *OrderService) GetOrderByToken(token string) (*models.Order, error) {
+	var order models.Order
+	err := s.DB.
+		Preload("OrderItems.Menu").
+		Preload("Table").
+		Where("tracking_token = ?", token).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func applyOrderCharges(order *models.Order, charges helper.OrderCharges) {
+	order.Subtotal = charges.Subtotal
+	order.ServiceRate = charges.ServiceRate
+	order.ServiceCharge = charges.ServiceCharge
+	order.TaxRate = charges.TaxRate
+	order.Tax = charges.Tax
+	order.Total = charges.Total
+}
+
+// Status order yang boleh dituju dari status sekarang
+var orderTransitions = map[string][]string{
+	models.OrderOpen:   {models.OrderServed, models.OrderCancelled},
+	models.OrderServed: {models.OrderPaid, models.OrderOpen}, // open lagi kalau ada item tambahan
+	models.OrderPaid:   {models.OrderRefunded},
+}
+
+func canTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Order yang masih menempati meja
+var activeOrderStatuses = []string{models.OrderOpen, models.OrderServed}
+
+func lockOrder(tx *gorm.DB, id uint) (*models.Order, error) {
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Pindah status order + catat riwayat, dipanggil di dalam transaksi
+func transitionOrder(tx *gorm.DB, order *models.Order, to string, actor string, note string) error {
+	if !canTransitionOrder(order.Status, to) {
+		return fmt.Errorf("cannot change order from %s to %s", order.Status, to)
+	}
+
+	switch to {
+	case models.OrderServed:
+		if err := serveReadyItems(tx, order.ID); err != nil {
+			return err
+		}
+	case models.OrderCancelled:
+		// uang yang sudah masuk tidak boleh hilang bersama order yang batal
+		var payments int64
+		if err := tx.Model(&models.Payment{}).Where("order_id = ?", order.ID).Count(&payments).Error; err != nil {
+			return err
+		}
+		if payments > 0 {
+			return ErrOrderHasPayments
+		}
+	}
+
+	from := order.Status
+	order.Status = to
+	order.UpdatedAt = time.Now()
+	if err := tx.Save(order).Error; err != nil {
+		return err
+	}
+
+	// stok bahan dipotong saat order diantar / dibayar
+	if to == models.OrderServed || to == models.OrderPaid {
+		if err := deductOrderStock(tx, order.ID, actor); err != nil {
+			return err
+		}
+	}
+
+	// order batal, item yang belum diantar ikut di-void supaya hilang dari layar dapur
+	if to == models.OrderCancelled {
+		if err := voidPendingItems(tx, order.ID, "order cancelled", actor); err != nil {
+			return err
+		}
+	}
+
+	// order lunas / batal, meja dilepas kalau tidak ada order lain yang masih jalan
+	if to == models.OrderPaid || to == models.OrderCancelled {
+		if err := releaseTableIfIdle(tx, order.TableID); err != nil {
+			return err
+		}
+	}
+
+	return recordOrderStatus(tx, order.ID, from, to, actor, note)
+}
+
+func recordOrderStatus(tx *gorm.DB, orderID uint, from, to, actor, note string) error {
+	return tx.Create(&models.OrderStatusHistory{
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Note:       note,
+		CreatedAt:  time.Now(),
+	}).Error
+}
+
+// Order hanya bisa served kalau dapur sudah selesai, item yang ready sekalian ditandai diantar
+func serveReadyItems(tx *gorm.DB, orderID uint) error {
+	var pending int64
+	if err := tx.Model(&models.OrderItem{}).
+		Where("order_id = ? AND prep_status IN ?", orderID, []string{models.PrepQueued, models.PrepPreparing}).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return ErrOrderItemsNotReady
+	}
+
+	var items []models.OrderItem
+	if err := tx.Where("order_id = ? AND prep_status = ?", orderID, models.PrepReady).Find(&items).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, item := range items {
+		item.PrepStatus = models.PrepServed
+		item.ServedAt = &now
+		if err := tx.Save(&item).Error; err != nil {
+			return err
+		}
+		publishAfterCommit(tx, events.TopicKitchen, events.OrderItemStatus, item)
+	}
+	return nil
+}
+
+func voidPendingItems(tx *gorm.DB, orderID uint, reason string, actor string) error {
+	var items []models.OrderItem
+	if err := tx.Where("order_id = ? AND prep_status IN ?", orderID,
+		[]string{models.PrepQueued, models.PrepPreparing, models.PrepReady}).
+		Find(&items).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		item.PrepStatus = models.PrepVoided
+		item.VoidedAt = &now
+		item.VoidReason = reason
+		if err := tx.Save(&item).Error; err != nil {
+			return err
+		}
+		if err := recordItemChange(tx, item, models.ItemVoided, item.Quantity, 0, reason, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func releaseTableIfIdle(tx *gorm.DB, tableID uint) error {
+	var active int64
+	if err := tx.Model(&models.Order{}).
+		Where("table_id = ? AND status IN ?", tableID, activeOrderStatuses).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
+		return nil
+	}
+	return tx.Model(&models.Table{}).Where("id = ?", tableID).Update("status", "available").Error
 }
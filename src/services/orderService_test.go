@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"titik-rindang/src/models"
+)
+
+func TestCreateOrderRollsBackOnMissingMenu(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Kopi Susu", 18000)
+
+	items := []OrderItemInput{
+		{MenuID: menu.ID, Qty: 1},
+		{MenuID: menu.ID + 999, Qty: 2}, // menu tidak ada
+	}
+	if _, err := NewOrderService(db).CreateOrder(table.ID, "Budi", items, "tester"); err == nil {
+		t.Fatal("expected error for missing menu_id")
+	}
+
+	if n := countRows(t, db, &models.Order{}); n != 0 {
+		t.Errorf("orders rows = %d, want 0", n)
+	}
+	if n := countRows(t, db, &models.OrderItem{}); n != 0 {
+		t.Errorf("order_items rows = %d, want 0", n)
+	}
+	if n := countRows(t, db, &models.OrderStatusHistory{}); n != 0 {
+		t.Errorf("order_status_histories rows = %d, want 0", n)
+	}
+
+	var after models.Table
+	if err := db.First(&after, table.ID).Error; err != nil {
+		t.Fatalf("reload table: %v", err)
+	}
+	if after.Status != table.Status {
+		t.Errorf("table status = %q, want unchanged %q", after.Status, table.Status)
+	}
+}
+
+func TestCreateOrderOccupiesTable(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Kopi Susu", 18000)
+
+	order, err := NewOrderService(db).CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 2}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if len(order.OrderItems) != 1 || order.Subtotal != 36000 {
+		t.Errorf("got %d items, subtotal %d; want 1 item, subtotal 36000", len(order.OrderItems), order.Subtotal)
+	}
+
+	var after models.Table
+	db.First(&after, table.ID)
+	if after.Status != "in_use" {
+		t.Errorf("table status = %q, want in_use", after.Status)
+	}
+}
+
+func TestCancelOrderVoidsItemsAndReleasesTable(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Nasi Goreng", 25000)
+
+	svc := NewOrderService(db)
+	order, err := svc.CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 1}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if _, err := svc.ChangeStatus(order.ID, models.OrderCancelled, "tester", "customer left"); err != nil {
+		t.Fatalf("cancel order: %v", err)
+	}
+
+	queue, err := NewKitchenService(db).GetQueue("")
+	if err != nil {
+		t.Fatalf("GetQueue: %v", err)
+	}
+	if len(queue) != 0 {
+		t.Errorf("kitchen queue has %d items after cancel, want 0", len(queue))
+	}
+
+	var item models.OrderItem
+	db.Where("order_id = ?", order.ID).First(&item)
+	if item.PrepStatus != models.PrepVoided || item.VoidReason == "" {
+		t.Errorf("item prep_status = %q, reason %q; want voided with reason", item.PrepStatus, item.VoidReason)
+	}
+
+	var after models.Table
+	db.First(&after, table.ID)
+	if after.Status != "available" {
+		t.Errorf("table status = %q, want available", after.Status)
+	}
+}
+
+func TestPaidOrderReleasesTable(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Es Teh", 8000)
+
+	svc := NewOrderService(db)
+	order, err := svc.CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 1}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	serveTestOrder(t, db, order.ID)
+	paid, err := svc.ConfirmOrder(order.ID, "cash", "tester")
+	if err != nil {
+		t.Fatalf("ConfirmOrder: %v", err)
+	}
+	if paid.Status != models.OrderPaid {
+		t.Fatalf("order status = %q, want paid", paid.Status)
+	}
+
+	var after models.Table
+	db.First(&after, table.ID)
+	if after.Status != "available" {
+		t.Errorf("table status = %q, want available", after.Status)
+	}
+}
+
+func TestDeleteOrderOnlyRemovesUntouchedOpenOrders(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Es Teh", 8000)
+	svc := NewOrderService(db)
+
+	served, err := svc.CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 1}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	serveTestOrder(t, db, served.ID)
+	if _, err := svc.AddPayment(served.ID, PaymentInput{Amount: 1000, Method: "cash"}, "tester"); err != nil {
+		t.Fatalf("partial payment: %v", err)
+	}
+	// dibuka lagi, statusnya open tapi sudah ada pembayaran
+	if _, err := svc.ChangeStatus(served.ID, models.OrderOpen, "tester", "extra item"); err != nil {
+		t.Fatalf("reopen order: %v", err)
+	}
+	if err := svc.DeleteOrder(served.ID); !errors.Is(err, ErrOrderNotDeletable) {
+		t.Fatalf("DeleteOrder on paid order error = %v, want ErrOrderNotDeletable", err)
+	}
+	if n := countRows(t, db, &models.Payment{}); n != 1 {
+		t.Errorf("payments rows = %d, want 1", n)
+	}
+
+	fresh, err := svc.CreateOrder(table.ID, "Sari", []OrderItemInput{{MenuID: menu.ID, Qty: 2}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if err := svc.DeleteOrder(fresh.ID); err != nil {
+		t.Fatalf("DeleteOrder: %v", err)
+	}
+	if n := countRows(t, db, &models.Order{}); n != 1 {
+		t.Errorf("orders rows = %d, want 1", n)
+	}
+
+	// order pertama masih aktif, meja tetap dipakai
+	var after models.Table
+	db.First(&after, table.ID)
+	if after.Status != "in_use" {
+		t.Errorf("table status = %q, want in_use", after.Status)
+	}
+}
+
+func TestCashRoundingOnlyAppliesToCashPayment(t *testing.T) {
+	t.Setenv("SERVICE_CHARGE_PERCENT", "5")
+	t.Setenv("PB1_TAX_PERCENT", "10")
+	t.Setenv("CASH_ROUNDING", "100")
+
+	db := openTestDB(t)
+	menu := createTestMenu(t, db, "Kopi Susu", 18500)
+	svc := NewOrderService(db)
+
+	pay := func(tableNo int, method string) *models.Order {
+		t.Helper()
+		table := createTestTable(t, db, tableNo)
+		order, err := svc.CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 1}}, "tester")
+		if err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		if order.Total != 21368 || order.Rounding != 0 {
+			t.Fatalf("order Total/Rounding = %d/%d, want 21368/0 before payment", order.Total, order.Rounding)
+		}
+		serveTestOrder(t, db, order.ID)
+		paid, err := svc.AddPayment(order.ID, PaymentInput{Method: method}, "tester")
+		if err != nil {
+			t.Fatalf("AddPayment(%s): %v", method, err)
+		}
+		if paid.Status != models.OrderPaid {
+			t.Fatalf("order status after %s = %q, want paid", method, paid.Status)
+		}
+		if balance, _ := svc.Balance(order.ID); balance != 0 {
+			t.Errorf("balance after %s = %d, want 0", method, balance)
+		}
+		return paid
+	}
+
+	cash := pay(1, models.PaymentCash)
+	if cash.Total != 21368 || cash.Rounding != 32 || cash.Payments[0].Amount != 21400 {
+		t.Errorf("cash Total/Rounding/Amount = %d/%d/%d, want 21368/32/21400", cash.Total, cash.Rounding, cash.Payments[0].Amount)
+	}
+
+	qris := pay(2, "qris")
+	if qris.Total != 21368 || qris.Rounding != 0 || qris.Payments[0].Amount != 21368 {
+		t.Errorf("qris Total/Rounding/Amount = %d/%d/%d, want 21368/0/21368", qris.Total, qris.Rounding, qris.Payments[0].Amount)
+	}
+}
+
+func TestServeAndCancelGuards(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Es Teh", 8000)
+	svc := NewOrderService(db)
+
+	order, err := svc.CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 1}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if _, err := svc.ChangeStatus(order.ID, models.OrderServed, "tester", ""); !errors.Is(err, ErrOrderItemsNotReady) {
+		t.Fatalf("serve with queued items error = %v, want ErrOrderItemsNotReady", err)
+	}
+
+	served := serveTestOrder(t, db, order.ID)
+	for _, item := range served.OrderItems {
+		if item.PrepStatus != models.PrepServed || item.ServedAt == nil {
+			t.Errorf("item %d PrepStatus/ServedAt = %s/%v, want served with time", item.ID, item.PrepStatus, item.ServedAt)
+		}
+	}
+
+	if _, err := svc.AddPayment(order.ID, PaymentInput{Amount: 1000, Method: "cash"}, "tester"); err != nil {
+		t.Fatalf("partial payment: %v", err)
+	}
+	if _, err := svc.ChangeStatus(order.ID, models.OrderOpen, "tester", "extra item"); err != nil {
+		t.Fatalf("reopen order: %v", err)
+	}
+	if _, err := svc.ChangeStatus(order.ID, models.OrderCancelled, "tester", ""); !errors.Is(err, ErrOrderHasPayments) {
+		t.Fatalf("cancel paid order error = %v, want ErrOrderHasPayments", err)
+	}
+	if n := countRows(t, db, &models.Payment{}); n != 1 {
+		t.Errorf("payments rows = %d, want 1", n)
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"titik-rindang/src/helper"
+	"titik-rindang/src/models"
+	"titik-rindang/src/payments"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentService struct {
+	DB       *gorm.DB
+	Provider payments.PaymentProvider
+}
+
+func NewPaymentService(db *gorm.DB) *PaymentService {
+	return &PaymentService{DB: db, Provider: payments.Default()}
+}
+
+// 🔹 Buat tagihan untuk order (dicari lewat tracking token)
+func (s *PaymentService) CreateOrderIntent(trackingToken string, method string) (*models.PaymentIntent, error) {
+	order, err := NewOrderService(s.DB).GetOrderByToken(trackingToken)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderServed {
+		return nil, fmt.Errorf("order cannot be paid while %s", order.Status)
+	}
+
+	// tagihan mengikuti sisa yang belum dibayar (split bill)
+	balance, err := NewOrderService(s.DB).Balance(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createIntent(payments.IntentRequest{
+		Reference:   fmt.Sprintf("ORDER-%d", order.ID),
+		Amount:      balance,
+		Method:      method,
+		Description: "Titik Rindang order #" + fmt.Sprint(order.ID),
+	}, &order.ID, nil)
+}
+
+// 🔹 Buat tagihan untuk biaya reservasi (dicari lewat payment token)
+func (s *PaymentService) CreateReservationIntent(paymentToken string, method string) (*models.PaymentIntent, error) {
+	reservation, err := NewReservationService(s.DB).GetReservationByToken(paymentToken)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != "Unpaid" {
+		return nil, errors.New("reservation is already " + reservation.Status)
+	}
+
+	return s.createIntent(payments.IntentRequest{
+		Reference:   fmt.Sprintf("RSV-%d", reservation.ID),
+		Amount:      reservation.TableFee,
+		Method:      method,
+		Description: "Titik Rindang reservation #" + fmt.Sprint(reservation.ID),
+	}, nil, &reservation.ID)
+}
+
+func (s *PaymentService) createIntent(req payments.IntentRequest, orderID, reservationID *uint) (*models.PaymentIntent, error) {
+	// tagihan lama yang lewat batas waktu ditutup dulu
+	if err := expireIntents(s.DB, orderID, reservationID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.Provider.CreateIntent(req)
+	if err != nil {
+		return nil, err
+	}
+
+	intent := models.PaymentIntent{
+		Provider:      s.Provider.Name(),
+		ProviderRef:   result.ProviderRef,
+		OrderID:       orderID,
+		ReservationID: reservationID,
+		Amount:        req.Amount,
+		Method:        req.Method,
+		Status:        payments.StatusPending,
+		QRString:      result.QRString,
+		RedirectURL:   result.RedirectURL,
+		ExpiresAt:     result.ExpiresAt,
+	}
+	if err := s.DB.Create(&intent).Error; err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// 🔹 Proses callback provider. Aman dipanggil berulang untuk callback yang sama.
+// Dana yang sudah diterima provider selalu dicatat di intent. Kalau tidak bisa dibukukan ke
+// order / reservasi (order dibuka lagi, sudah dibayar, nominal beda, dll), intent ditandai
+// NeedsReconciliation, bukan di-rollback.
+func (s *PaymentService) HandleWebhook(header http.Header, body []byte) (*models.PaymentIntent, error) {
+	event, err := s.Provider.VerifyWebhook(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var intent models.PaymentIntent
+	var paidReservation *models.Reservation
+
+	err = transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider = ? AND provider_ref = ?", s.Provider.Name(), event.ProviderRef).
+			First(&intent).Error; err != nil {
+			return errors.New("payment intent not found")
+		}
+
+		// callback duplikat
+		if intent.Status == payments.StatusPaid {
+			return nil
+		}
+
+		if event.Status != payments.StatusPaid {
+			intent.Status = event.Status
+			return tx.Save(&intent).Error
+		}
+
+		now := time.Now()
+		intent.Status = payments.StatusPaid
+		intent.PaidAt = &now
+
+		// savepoint: kalau gagal dibukukan, hanya pembukuannya yang batal
+		applyErr := transactionWithEvents(tx, func(tx *gorm.DB) error {
+			if event.Amount != intent.Amount {
+				return fmt.Errorf("paid amount %s does not match %s", event.Amount, intent.Amount)
+			}
+			var err error
+			paidReservation, err = applyIntentPayment(tx, &intent)
+			return err
+		})
+		if applyErr != nil {
+			paidReservation = nil
+			intent.NeedsReconciliation = true
+			intent.ReconcileNote = applyErr.Error()
+			log.Printf("payment %s captured but not applied, needs reconciliation: %v", intent.ProviderRef, applyErr)
+		}
+
+		return tx.Save(&intent).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if paidReservation != nil {
+		var invoice models.Invoice
+		if err := s.DB.Where("reservation_id = ?", paidReservation.ID).First(&invoice).Error; err == nil {
+			if err := helper.SendInvoiceEmail(paidReservation.Email, &invoice); err != nil {
+				log.Printf("failed to send invoice email for reservation %d: %v", paidReservation.ID, err)
+			}
+		}
+	}
+
+	return &intent, nil
+}
+
+// Bukukan intent yang sudah lunas ke order / reservasinya
+func applyIntentPayment(tx *gorm.DB, intent *models.PaymentIntent) (*models.Reservation, error) {
+	actor := "payment:" + intent.Provider
+	if intent.OrderID != nil {
+		_, err := NewOrderService(tx).AddPayment(*intent.OrderID, PaymentInput{
+			Amount:          intent.Amount,
+			Method:          intent.Method,
+			PaymentIntentID: &intent.ID,
+		}, actor)
+		return nil, err
+	}
+	if intent.ReservationID != nil {
+		reservationSvc := NewReservationService(tx)
+		reservation, err := reservationSvc.GetReservationByID(*intent.ReservationID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := reservationSvc.ConfirmReservation(reservation); err != nil {
+			return nil, err
+		}
+		return reservation, nil
+	}
+	return nil, nil
+}
+
+// 🔹 Tagihan yang sudah dibayar tapi belum terbukukan
+func (s *PaymentService) GetUnreconciled() ([]models.PaymentIntent, error) {
+	var intents []models.PaymentIntent
+	err := s.DB.Where("needs_reconciliation = ?", true).Order("paid_at ASC").Find(&intents).Error
+	return intents, err
+}
+
+// 🔹 Cek status tagihan
+func (s *PaymentService) GetIntent(providerRef string) (*models.PaymentIntent, error) {
+	var intent models.PaymentIntent
+	if err := s.DB.Where("provider_ref = ?", providerRef).First(&intent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment intent not found")
+		}
+		return nil, err
+	}
+
+	// hanya ditampilkan expired, endpoint baca tidak mengubah data (lihat expireIntents)
+	if intent.Status == payments.StatusPending && time.Now().After(intent.ExpiresAt) {
+		intent.Status = payments.StatusExpired
+	}
+	return &intent, nil
+}
+
+// Tandai expired tagihan pending milik order / reservasi yang sudah lewat batas waktu
+func expireIntents(tx *gorm.DB, orderID, reservationID *uint) error {
+	query := tx.Model(&models.PaymentIntent{}).
+		Where("status = ? AND expires_at < ?", payments.StatusPending, time.Now())
+	switch {
+	case orderID != nil:
+		query = query.Where("order_id = ?", *orderID)
+	case reservationID != nil:
+		query = query.Where("reservation_id = ?", *reservationID)
+	default:
+		return nil
+	}
+	return query.Update("status", payments.StatusExpired).Error
+}
+
+// 🔹 Simulasi pembayaran sukses, hanya untuk fake provider
+func (s *PaymentService) SimulatePayment(providerRef string) (*models.PaymentIntent, error) {
+	fake, ok := s.Provider.(*payments.FakeProvider)
+	if !ok {
+		return nil, errors.New("payment simulation is only available for the fake provider")
+	}
+
+	intent, err := s.GetIntent(providerRef)
+	if err != nil {
+		return nil, err
+	}
+
+	header, body, err := fake.SimulateWebhook(intent.ProviderRef, payments.StatusPaid, intent.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return s.HandleWebhook(header, body)
+}
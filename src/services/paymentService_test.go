@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"titik-rindang/src/models"
+	"titik-rindang/src/payments"
+
+	"gorm.io/gorm"
+)
+
+// Order yang sudah diantar dan siap dibayar lewat provider
+func servedTestOrder(t *testing.T, db *gorm.DB) *models.Order {
+	t.Helper()
+	table := createTestTable(t, db, 1)
+	menu := createTestMenu(t, db, "Kopi Susu", 18000)
+
+	svc := NewOrderService(db)
+	order, err := svc.CreateOrder(table.ID, "Budi", []OrderItemInput{{MenuID: menu.ID, Qty: 2}}, "tester")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	return serveTestOrder(t, db, order.ID)
+}
+
+func newTestPaymentService(db *gorm.DB) (*PaymentService, *payments.FakeProvider) {
+	fake := payments.NewFakeProvider("test-webhook-secret")
+	return &PaymentService{DB: db, Provider: fake}, fake
+}
+
+func TestHandleWebhookPaysOrder(t *testing.T) {
+	db := openTestDB(t)
+	order := servedTestOrder(t, db)
+	svc, fake := newTestPaymentService(db)
+
+	intent, err := svc.CreateOrderIntent(order.TrackingToken, payments.MethodQRIS)
+	if err != nil {
+		t.Fatalf("CreateOrderIntent: %v", err)
+	}
+	if intent.Amount != order.Total {
+		t.Fatalf("intent amount = %s, want order total %s", intent.Amount, order.Total)
+	}
+
+	header, body, err := fake.SimulateWebhook(intent.ProviderRef, payments.StatusPaid, intent.Amount)
+	if err != nil {
+		t.Fatalf("SimulateWebhook: %v", err)
+	}
+	paid, err := svc.HandleWebhook(header, body)
+	if err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	if paid.Status != payments.StatusPaid || paid.PaidAt == nil || paid.NeedsReconciliation {
+		t.Errorf("intent status = %q, paid_at %v, reconcile %v; want paid and applied", paid.Status, paid.PaidAt, paid.NeedsReconciliation)
+	}
+
+	var after models.Order
+	db.First(&after, order.ID)
+	if after.Status != models.OrderPaid {
+		t.Errorf("order status = %q, want paid", after.Status)
+	}
+
+	// callback yang sama dikirim ulang tidak membuat payment dobel
+	if _, err := svc.HandleWebhook(header, body); err != nil {
+		t.Fatalf("duplicate HandleWebhook: %v", err)
+	}
+	var recorded []models.Payment
+	db.Where("order_id = ?", order.ID).Find(&recorded)
+	if len(recorded) != 1 || recorded[0].PaymentIntentID == nil || *recorded[0].PaymentIntentID != intent.ID {
+		t.Errorf("got %d payments, want exactly one linked to intent %d", len(recorded), intent.ID)
+	}
+}
+
+func TestHandleWebhookRejectsBadSignature(t *testing.T) {
+	db := openTestDB(t)
+	order := servedTestOrder(t, db)
+	svc, fake := newTestPaymentService(db)
+
+	intent, err := svc.CreateOrderIntent(order.TrackingToken, payments.MethodQRIS)
+	if err != nil {
+		t.Fatalf("CreateOrderIntent: %v", err)
+	}
+
+	header, body, err := fake.SimulateWebhook(intent.ProviderRef, payments.StatusPaid, intent.Amount)
+	if err != nil {
+		t.Fatalf("SimulateWebhook: %v", err)
+	}
+	header.Set(payments.SignatureHeader, "forged")
+
+	if _, err := svc.HandleWebhook(header, body); !errors.Is(err, payments.ErrInvalidSignature) {
+		t.Fatalf("HandleWebhook error = %v, want ErrInvalidSignature", err)
+	}
+
+	// body ditandatangani dengan secret lain juga ditolak
+	other := payments.NewFakeProvider("other-secret")
+	header, body, _ = other.SimulateWebhook(intent.ProviderRef, payments.StatusPaid, intent.Amount)
+	if _, err := svc.HandleWebhook(header, body); !errors.Is(err, payments.ErrInvalidSignature) {
+		t.Fatalf("HandleWebhook error = %v, want ErrInvalidSignature", err)
+	}
+
+	var after models.PaymentIntent
+	db.First(&after, intent.ID)
+	if after.Status != payments.StatusPending {
+		t.Errorf("intent status = %q, want pending", after.Status)
+	}
+	if n := countRows(t, db, &models.Payment{}); n != 0 {
+		t.Errorf("payments rows = %d, want 0", n)
+	}
+}
+
+func TestHandleWebhookFlagsCaptureThatCannotBeApplied(t *testing.T) {
+	db := openTestDB(t)
+	order := servedTestOrder(t, db)
+	svc, fake := newTestPaymentService(db)
+
+	intent, err := svc.CreateOrderIntent(order.TrackingToken, payments.MethodQRIS)
+	if err != nil {
+		t.Fatalf("CreateOrderIntent: %v", err)
+	}
+
+	// order dibuka lagi (tambah item) sebelum callback datang
+	if _, err := NewOrderService(db).ChangeStatus(order.ID, models.OrderOpen, "tester", "extra item"); err != nil {
+		t.Fatalf("reopen order: %v", err)
+	}
+
+	header, body, err := fake.SimulateWebhook(intent.ProviderRef, payments.StatusPaid, intent.Amount)
+	if err != nil {
+		t.Fatalf("SimulateWebhook: %v", err)
+	}
+	result, err := svc.HandleWebhook(header, body)
+	if err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	if result.Status != payments.StatusPaid || !result.NeedsReconciliation || result.ReconcileNote == "" {
+		t.Errorf("intent status = %q, reconcile %v, note %q; want paid and flagged", result.Status, result.NeedsReconciliation, result.ReconcileNote)
+	}
+
+	var stored models.PaymentIntent
+	db.First(&stored, intent.ID)
+	if stored.Status != payments.StatusPaid || !stored.NeedsReconciliation {
+		t.Errorf("stored intent status = %q, reconcile %v; capture must not be rolled back", stored.Status, stored.NeedsReconciliation)
+	}
+	if n := countRows(t, db, &models.Payment{}); n != 0 {
+		t.Errorf("payments rows = %d, want 0", n)
+	}
+
+	unreconciled, err := svc.GetUnreconciled()
+	if err != nil {
+		t.Fatalf("GetUnreconciled: %v", err)
+	}
+	if len(unreconciled) != 1 || unreconciled[0].ID != intent.ID {
+		t.Errorf("got %d unreconciled intents, want intent %d", len(unreconciled), intent.ID)
+	}
+}
+
+func TestHandleWebhookWithoutSecretIsRejected(t *testing.T) {
+	svc := &PaymentService{Provider: payments.NewFakeProvider("")}
+	if _, err := svc.HandleWebhook(nil, []byte(`{"reference":"FAKE-x","status":"paid","amount":1000}`)); !errors.Is(err, payments.ErrWebhookSecretMissing) {
+		t.Fatalf("HandleWebhook error = %v, want ErrWebhookSecretMissing", err)
+	}
+}
+
+func TestReservationIntentUsesPaymentTokenAndStatusIsReadOnly(t *testing.T) {
+	t.Setenv("RESERVATION_FEE", "50000")
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	reservation := models.Reservation{Name: "Sari", Phone: "08123456789", TableID: table.ID, PartySize: 2, ReservationDate: time.Now().Add(48 * time.Hour)}
+	if err := NewReservationService(db).CreateReservation(&reservation); err != nil {
+		t.Fatalf("CreateReservation: %v", err)
+	}
+	if reservation.PaymentToken == "" {
+		t.Fatal("reservation has no payment token")
+	}
+
+	svc, _ := newTestPaymentService(db)
+	if _, err := svc.CreateReservationIntent(fmt.Sprint(reservation.ID), payments.MethodQRIS); err == nil {
+		t.Error("reservation intent was created from the sequential ID")
+	}
+	intent, err := svc.CreateReservationIntent(reservation.PaymentToken, payments.MethodQRIS)
+	if err != nil {
+		t.Fatalf("CreateReservationIntent: %v", err)
+	}
+
+	// tagihan lewat batas waktu: GET hanya menampilkan expired, tidak menyimpan
+	db.Model(intent).Update("expires_at", time.Now().Add(-time.Minute))
+	shown, err := svc.GetIntent(intent.ProviderRef)
+	if err != nil {
+		t.Fatalf("GetIntent: %v", err)
+	}
+	if shown.Status != payments.StatusExpired {
+		t.Errorf("shown status = %q, want expired", shown.Status)
+	}
+	var stored models.PaymentIntent
+	db.First(&stored, intent.ID)
+	if stored.Status != payments.StatusPending {
+		t.Errorf("stored status after GetIntent = %q, want pending", stored.Status)
+	}
+
+	// tagihan baru menutup tagihan lama yang sudah lewat
+	if _, err := svc.CreateReservationIntent(reservation.PaymentToken, payments.MethodQRIS); err != nil {
+		t.Fatalf("second CreateReservationIntent: %v", err)
+	}
+	db.First(&stored, intent.ID)
+	if stored.Status != payments.StatusExpired {
+		t.Errorf("stored status after new intent = %q, want expired", stored.Status)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"titik-rindang/src/models"
+
+	"github.com/signintech/gopdf"
+)
+
+// Cetak PO ke PDF untuk dikirim ke supplier, layout mengikuti struk order.
+// File disimpan ke storage, yang dikembalikan URL-nya.
+func (s *PurchaseService) GeneratePDF(po *models.PurchaseOrder) (string, error) {
+	pdf := &gopdf.GoPdf{}
+	pdf.Start(gopdf.Config{PageSize: *gopdf.PageSizeA4})
+	pdf.AddPage()
+
+	pageWidth := gopdf.PageSizeA4.W
+	leftMargin := 50.0
+	rightMargin := 50.0
+	contentWidth := pageWidth - leftMargin - rightMargin
+
+	_ = pdf.AddTTFFont("regular", "src/fonts/Roboto-Regular.ttf")
+	_ = pdf.AddTTFFont("bold", "src/fonts/Roboto-Bold.ttf")
+
+	pdf.SetMargins(leftMargin, 40, rightMargin, 0)
+
+	pdf.SetFont("bold", "", 20)
+	pdf.SetX(leftMargin)
+	pdf.CellWithOption(
+		&gopdf.Rect{W: contentWidth, H: 20},
+		"PURCHASE ORDER",
+		gopdf.CellOption{Align: gopdf.Center},
+	)
+	pdf.Br(24)
+
+	pdf.SetFont("regular", "", 12)
+	pdf.SetX(leftMargin)
+	pdf.CellWithOption(
+		&gopdf.Rect{W: contentWidth, H: 14},
+		"Titik Rindang Cafe - Jl. Rindang Hijau No. 123, Puncak Pass, Kab. Bogor",
+		gopdf.CellOption{Align: gopdf.Center},
+	)
+	pdf.Br(24)
+
+	drawLine(pdf, leftMargin, pageWidth-rightMargin)
+	pdf.Br(16)
+
+	drawKeyValue(pdf, leftMargin, "No. PO", po.Number)
+	drawKeyValue(pdf, leftMargin, "Tanggal", po.CreatedAt.Format("02 Jan 2006"))
+	if po.ExpectedAt != nil {
+		drawKeyValue(pdf, leftMargin, "Dikirim", po.ExpectedAt.Format("02 Jan 2006"))
+	}
+	drawKeyValue(pdf, leftMargin, "Supplier", po.Supplier.Name)
+	if po.Supplier.Phone != "" {
+		drawKeyValue(pdf, leftMargin, "Telepon", po.Supplier.Phone)
+	}
+	if po.Supplier.Address != "" {
+		drawKeyValue(pdf, leftMargin, "Alamat", truncate(po.Supplier.Address, 50))
+	}
+	drawKeyValue(pdf, leftMargin, "Status", po.Status)
+	pdf.Br(10)
+
+	pdf.SetFont("bold", "", 12)
+
+	colWidths := []float64{190, 90, 100, 100}
+	tableStartX := leftMargin
+	tableStartY := pdf.GetY()
+
+	drawTableHeader(pdf, tableStartX, tableStartY, colWidths, []string{"Bahan", "Qty", "Harga/Satuan", "Subtotal"})
+
+	pdf.SetFont("regular", "", 12)
+
+	y := tableStartY + 20
+	rowHeight := 20.0
+
+	for _, item := range po.Items {
+		drawTableRow(pdf, tableStartX, y, colWidths, rowHeight, []string{
+			truncate(item.Ingredient.Name, 25),
+			strconv.FormatFloat(item.Quantity, 'f', -1, 64) + " " + item.Ingredient.Unit,
+			item.UnitCost.String(),
+			item.Subtotal().String(),
+		})
+		y += rowHeight
+	}
+
+	pdf.SetY(y + 16)
+
+	pdf.SetFont("bold", "", 14)
+	pdf.SetX(leftMargin + colWidths[0] + colWidths[1] + 30)
+	pdf.Cell(nil, "TOTAL :")
+	pdf.SetX(leftMargin + colWidths[0] + colWidths[1] + colWidths[2])
+	pdf.Cell(nil, po.Total.String())
+	pdf.Br(24)
+
+	if po.Notes != "" {
+		pdf.SetFont("regular", "", 12)
+		drawKeyValue(pdf, leftMargin, "Catatan", truncate(po.Notes, 50))
+		pdf.Br(10)
+	}
+
+	drawLine(pdf, leftMargin, pageWidth-rightMargin)
+	pdf.Br(20)
+
+	pdf.SetFont("regular", "", 12)
+	drawKeyValue(pdf, leftMargin, "Dibuat oleh", po.CreatedBy)
+
+	var buf bytes.Buffer
+	if _, err := pdf.WriteTo(&buf); err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("purchase-orders/%s.pdf", po.Number)
+	if err := s.Storage.Put(key, &buf, "application/pdf"); err != nil {
+		return "", err
+	}
+
+	return s.Storage.URL(key), nil
+}
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"titik-rindang/src/models"
+	"titik-rindang/src/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PurchaseService struct {
+	DB      *gorm.DB
+	Storage storage.Storage
+}
+
+func NewPurchaseService(db *gorm.DB) *PurchaseService {
+	return &PurchaseService{DB: db, Storage: storage.Default()}
+}
+
+// Get All Suppliers
+func (s *PurchaseService) GetAllSuppliers() ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	err := s.DB.Order("name ASC").Find(&suppliers).Error
+	return suppliers, err
+}
+
+func (s *PurchaseService) GetSupplierByID(id uint) (*models.Supplier, error) {
+	var supplier models.Supplier
+	if err := s.DB.First(&supplier, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("supplier not found")
+		}
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// Create Supplier
+func (s *PurchaseService) CreateSupplier(supplier *models.Supplier) error {
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	if supplier.Name == "" {
+		return errors.New("supplier name is required")
+	}
+	return s.DB.Create(supplier).Error
+}
+
+// Update Supplier, field kosong tidak diubah
+func (s *PurchaseService) UpdateSupplier(id uint, updated *models.Supplier) (*models.Supplier, error) {
+	supplier, err := s.GetSupplierByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(updated.Name); name != "" {
+		supplier.Name = name
+	}
+	if updated.Phone != "" {
+		supplier.Phone = updated.Phone
+	}
+	if updated.Email != "" {
+		supplier.Email = updated.Email
+	}
+	if updated.Address != "" {
+		supplier.Address = updated.Address
+	}
+	if updated.Notes != "" {
+		supplier.Notes = updated.Notes
+	}
+
+	if err := s.DB.Save(supplier).Error; err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// Delete Supplier, ditolak kalau sudah punya PO
+func (s *PurchaseService) DeleteSupplier(id uint) error {
+	if _, err := s.GetSupplierByID(id); err != nil {
+		return err
+	}
+
+	var used int64
+	if err := s.DB.Model(&models.PurchaseOrder{}).Where("supplier_id = ?", id).Count(&used).Error; err != nil {
+		return err
+	}
+	if used > 0 {
+		return errors.New("supplier already has purchase orders")
+	}
+	return s.DB.Delete(&models.Supplier{}, id).Error
+}
+
+// Get All Purchase Orders (status opsional)
+func (s *PurchaseService) GetAllPurchaseOrders(status string) ([]models.PurchaseOrder, error) {
+	query := s.DB.Preload("Supplier").Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var orders []models.PurchaseOrder
+	err := query.Find(&orders).Error
+	return orders, err
+}
+
+// Get Purchase Order (lengkap dengan item & riwayat penerimaan)
+func (s *PurchaseService) GetPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	err := s.DB.
+		Preload("Supplier").
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Items.Ingredient", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }). // bahan yang sudah dihapus tetap tampil di PO lama
+		Preload("Receipts", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Receipts.Items").
+		First(&po, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase order not found")
+		}
+		return nil, err
+	}
+	return &po, nil
+}
+
+// Create Purchase Order (status draft)
+func (s *PurchaseService) CreatePurchaseOrder(po *models.PurchaseOrder, actor string) (*models.PurchaseOrder, error) {
+	if _, err := s.GetSupplierByID(po.SupplierID); err != nil {
+		return nil, err
+	}
+	if err := s.validateItems(po.Items); err != nil {
+		return nil, err
+	}
+
+	po.Status = models.PurchaseDraft
+	po.CreatedBy = actor
+	po.Total = purchaseTotal(po.Items)
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(po).Error; err != nil {
+			return err
+		}
+		return tx.Model(po).Update("number", fmt.Sprintf("PO-%05d", po.ID)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(po.ID)
+}
+
+// Update Purchase Order, hanya selama masih draft. Item diganti seluruhnya.
+func (s *PurchaseService) UpdatePurchaseOrder(id uint, updated *models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	po, err := s.GetPurchaseOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if po.Status != models.PurchaseDraft {
+		return nil, errors.New("only draft purchase orders can be edited")
+	}
+
+	if updated.SupplierID != 0 {
+		if _, err := s.GetSupplierByID(updated.SupplierID); err != nil {
+			return nil, err
+		}
+		po.SupplierID = updated.SupplierID
+	}
+	if updated.Notes != "" {
+		po.Notes = updated.Notes
+	}
+	if updated.ExpectedAt != nil {
+		po.ExpectedAt = updated.ExpectedAt
+	}
+
+	items := po.Items
+	if updated.Items != nil {
+		if err := s.validateItems(updated.Items); err != nil {
+			return nil, err
+		}
+		items = updated.Items
+	}
+	po.Total = purchaseTotal(items)
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Supplier", "Items", "Receipts").Save(po).Error; err != nil {
+			return err
+		}
+		if updated.Items == nil {
+			return nil
+		}
+		if err := tx.Where("purchase_order_id = ?", po.ID).Delete(&models.PurchaseOrderItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ID = 0
+			items[i].PurchaseOrderID = po.ID
+		}
+		return tx.Omit("Ingredient").Create(&items).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(id)
+}
+
+// Status PO yang boleh dituju dari status sekarang (penerimaan barang lewat ReceiveGoods)
+var purchaseTransitions = map[string][]string{
+	models.PurchaseDraft:   {models.PurchaseOrdered, models.PurchaseCancelled},
+	models.PurchaseOrdered: {models.PurchaseCancelled},
+}
+
+// Change Status: kirim ke supplier (ordered) atau batal (cancelled)
+func (s *PurchaseService) ChangeStatus(id uint, status string) (*models.PurchaseOrder, error) {
+	po, err := s.GetPurchaseOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, next := range purchaseTransitions[po.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("cannot change purchase order from %s to %s", po.Status, status)
+	}
+
+	updates := map[string]interface{}{"status": status}
+	if status == models.PurchaseOrdered {
+		updates["ordered_at"] = time.Now()
+	}
+	// hanya kalau status belum berubah (mis. barang keburu diterima)
+	result := s.DB.Model(&models.PurchaseOrder{}).Where("id = ? AND status = ?", id, po.Status).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("purchase order was changed by someone else, please reload")
+	}
+	return s.GetPurchaseOrder(id)
+}
+
+type ReceiveItemInput struct {
+	PurchaseOrderItemID uint
+	Quantity            float64
+	UnitCost            *models.Money // kosong = sesuai harga di PO
+}
+
+// Receive Goods: tambah stok, catat harga per satuan, PO jadi received kalau semua item lengkap
+func (s *PurchaseService) ReceiveGoods(id uint, items []ReceiveItemInput, note string, actor string) (*models.PurchaseOrder, error) {
+	if len(items) == 0 {
+		return nil, errors.New("received items are required")
+	}
+
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		// kunci PO dulu supaya dua penerimaan bersamaan tidak melewati batas qty yang dipesan
+		var po models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&po, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("purchase order not found")
+			}
+			return err
+		}
+		if err := tx.Where("purchase_order_id = ?", po.ID).Order("id ASC").Find(&po.Items).Error; err != nil {
+			return err
+		}
+		if po.Status != models.PurchaseOrdered && po.Status != models.PurchasePartiallyReceived {
+			return fmt.Errorf("cannot receive goods while purchase order is %s", po.Status)
+		}
+
+		lines := map[uint]*models.PurchaseOrderItem{}
+		for i := range po.Items {
+			lines[po.Items[i].ID] = &po.Items[i]
+		}
+
+		receipt := models.GoodsReceipt{
+			PurchaseOrderID: po.ID,
+			ReceivedBy:      actor,
+			Note:            note,
+			CreatedAt:       time.Now(),
+		}
+
+		for _, input := range items {
+			line, ok := lines[input.PurchaseOrderItemID]
+			if !ok {
+				return errors.New("some items do not belong to this purchase order")
+			}
+			if input.Quantity <= 0 {
+				return errors.New("received quantity must be greater than 0")
+			}
+			if line.ReceivedQty+input.Quantity > line.Quantity {
+				return fmt.Errorf("cannot receive more than ordered for item %d", line.ID)
+			}
+
+			unitCost := line.UnitCost
+			if input.UnitCost != nil {
+				if *input.UnitCost < 0 {
+					return errors.New("unit cost must not be negative")
+				}
+				unitCost = *input.UnitCost
+			}
+
+			line.ReceivedQty += input.Quantity
+			if err := tx.Model(line).Update("received_qty", line.ReceivedQty).Error; err != nil {
+				return err
+			}
+			if _, err := changeStock(tx, line.IngredientID, input.Quantity, models.StockRestock, po.Number, nil, actor); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Ingredient{}).Where("id = ?", line.IngredientID).Update("last_unit_cost", unitCost).Error; err != nil {
+				return err
+			}
+
+			receipt.Items = append(receipt.Items, models.GoodsReceiptItem{
+				PurchaseOrderItemID: line.ID,
+				IngredientID:        line.IngredientID,
+				Quantity:            input.Quantity,
+				UnitCost:            unitCost,
+			})
+		}
+
+		if err := tx.Create(&receipt).Error; err != nil {
+			return err
+		}
+
+		complete := true
+		for _, line := range po.Items {
+			if line.ReceivedQty < line.Quantity {
+				complete = false
+				break
+			}
+		}
+		updates := map[string]interface{}{"status": models.PurchasePartiallyReceived}
+		if complete {
+			updates["status"] = models.PurchaseReceived
+			updates["received_at"] = time.Now()
+		}
+		if err := tx.Model(&models.PurchaseOrder{}).Where("id = ?", po.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return refreshStockAvailability(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(id)
+}
+
+func (s *PurchaseService) validateItems(items []models.PurchaseOrderItem) error {
+	if len(items) == 0 {
+		return errors.New("purchase order must have at least one item")
+	}
+
+	inventory := NewInventoryService(s.DB)
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return errors.New("quantity must be greater than 0")
+		}
+		if item.UnitCost < 0 {
+			return errors.New("unit cost must not be negative")
+		}
+		if _, err := inventory.GetIngredientByID(item.IngredientID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func purchaseTotal(items []models.PurchaseOrderItem) models.Money {
+	total := models.Money(0)
+	for _, item := range items {
+		total += item.Subtotal()
+	}
+	return total
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"sync"
+	"testing"
+
+	"titik-rindang/src/models"
+)
+
+func TestConcurrentReceiveGoodsCannotExceedOrderedQty(t *testing.T) {
+	db := openTestDB(t)
+	inventory := NewInventoryService(db)
+	svc := NewPurchaseService(db)
+
+	ingredient := models.Ingredient{Name: "Biji Kopi", Unit: "g"}
+	if err := inventory.CreateIngredient(&ingredient, "tester"); err != nil {
+		t.Fatalf("CreateIngredient: %v", err)
+	}
+	supplier := models.Supplier{Name: "Roastery"}
+	if err := svc.CreateSupplier(&supplier); err != nil {
+		t.Fatalf("CreateSupplier: %v", err)
+	}
+	po, err := svc.CreatePurchaseOrder(&models.PurchaseOrder{
+		SupplierID: supplier.ID,
+		Items:      []models.PurchaseOrderItem{{IngredientID: ingredient.ID, Quantity: 1000, UnitCost: 200}},
+	}, "tester")
+	if err != nil {
+		t.Fatalf("CreatePurchaseOrder: %v", err)
+	}
+	if _, err := svc.ChangeStatus(po.ID, models.PurchaseOrdered); err != nil {
+		t.Fatalf("ChangeStatus: %v", err)
+	}
+
+	// dua kasir menerima 600 g bersamaan, total 1200 g melebihi pesanan 1000 g
+	var wg sync.WaitGroup
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.ReceiveGoods(po.ID, []ReceiveItemInput{{PurchaseOrderItemID: po.Items[0].ID, Quantity: 600}}, "", "tester")
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("successful receipts = %d, want 1", succeeded)
+	}
+
+	stored, err := svc.GetPurchaseOrder(po.ID)
+	if err != nil {
+		t.Fatalf("GetPurchaseOrder: %v", err)
+	}
+	if got := stored.Items[0].ReceivedQty; got != 600 {
+		t.Errorf("ReceivedQty = %v, want 600", got)
+	}
+	stock, err := inventory.GetIngredientByID(ingredient.ID)
+	if err != nil {
+		t.Fatalf("GetIngredientByID: %v", err)
+	}
+	if stock.Stock != 600 {
+		t.Errorf("stock = %v, want 600", stock.Stock)
+	}
+}
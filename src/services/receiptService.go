@@ -1,18 +1,22 @@
 package services
 
 import (
+	"bytes"
 	"fmt"
-	"os"
+	"strings"
 
 	"titik-rindang/src/models"
+	"titik-rindang/src/storage"
 
 	"github.com/signintech/gopdf"
 )
 
-type ReceiptService struct{}
+type ReceiptService struct {
+	Storage storage.Storage
+}
 
 func NewReceiptService() *ReceiptService {
-	return &ReceiptService{}
+	return &ReceiptService{Storage: storage.Default()}
 }
 
 func (s *ReceiptService) GenerateReceipt(order *models.Order) (string, error) {
@@ -83,7 +87,7 @@ func (s *ReceiptService) GenerateReceipt(order *models.Order) (string, error) {
 	tableStartX := leftMargin
 	tableStartY := pdf.GetY()
 
-	drawTableHeader(pdf, tableStartX, tableStartY, colWidths)
+	drawTableHeader(pdf, tableStartX, tableStartY, colWidths, []string{"Item", "Qty", "Harga", "Subtotal"})
 
 	pdf.SetFont("regular", "", 12)
 
@@ -91,28 +95,88 @@ func (s *ReceiptService) GenerateReceipt(order *models.Order) (string, error) {
 	rowHeight := 20.0
 
 	for _, item := range order.OrderItems {
+		// item yang di-void tetap tercetak supaya jelas, tapi tanpa nominal
+		if item.PrepStatus == models.PrepVoided {
+			drawTableRow(pdf, tableStartX, y, colWidths, rowHeight, []string{
+				truncate(item.MenuName, 25),
+				fmt.Sprintf("%d", item.Quantity),
+				"",
+				"VOID",
+			})
+			y += rowHeight
+			continue
+		}
+
 		drawTableRow(pdf, tableStartX, y, colWidths, rowHeight, []string{
-			truncate(item.Menu.Name, 25),
+			truncate(item.MenuName, 25),
 			fmt.Sprintf("%d", item.Quantity),
-			fmt.Sprintf("Rp %.0f", item.Menu.Price),
-			fmt.Sprintf("Rp %.0f", item.Subtotal),
+			item.UnitPrice.String(),
+			item.Subtotal.String(),
 		})
 		y += rowHeight
+
+		for _, modifier := range item.Modifiers {
+			delta := ""
+			if modifier.PriceDelta != 0 {
+				delta = "+" + modifier.PriceDelta.String()
+			}
+			drawTableRow(pdf, tableStartX, y, colWidths, rowHeight, []string{
+				truncate("  + "+modifier.Option, 25), "", delta, "",
+			})
+			y += rowHeight
+		}
 	}
 
 	drawLineAt(pdf, leftMargin, pageWidth-rightMargin, y)
 	y += 12
 
 	pdf.SetY(y + 10)
+
+	// subtotal -> service -> PB1 -> total, pembulatan tunai di bawah total
+	labelX := leftMargin + colWidths[0] + colWidths[1] + 30
+	valueX := leftMargin + colWidths[0] + colWidths[1] + colWidths[2]
+
+	pdf.SetFont("regular", "", 12)
+	drawSummaryRow(pdf, labelX, valueX, "Subtotal :", order.Subtotal)
+	if order.ServiceCharge != 0 {
+		drawSummaryRow(pdf, labelX, valueX, fmt.Sprintf("Service %g%% :", order.ServiceRate), order.ServiceCharge)
+	}
+	if order.Tax != 0 {
+		drawSummaryRow(pdf, labelX, valueX, fmt.Sprintf("PB1 %g%% :", order.TaxRate), order.Tax)
+	}
+	pdf.Br(4)
+
 	pdf.SetFont("bold", "", 14)
 
-	pdf.SetX(leftMargin + colWidths[0] + colWidths[1] + 30)
+	pdf.SetX(labelX)
 	pdf.Cell(nil, "TOTAL :")
 
-	pdf.SetX(leftMargin + colWidths[0] + colWidths[1] + colWidths[2])
-	pdf.Cell(nil, fmt.Sprintf("Rp %.0f", order.Total))
+	pdf.SetX(valueX)
+	pdf.Cell(nil, order.Total.String())
 	pdf.Br(24)
 
+	if order.Rounding != 0 {
+		pdf.SetFont("regular", "", 12)
+		drawSummaryRow(pdf, labelX, valueX, "Pembulatan :", order.Rounding)
+		drawSummaryRow(pdf, labelX, valueX, "Bayar Tunai :", order.Total+order.Rounding)
+		pdf.Br(10)
+	}
+
+	// rincian pembayaran (bisa lebih dari satu kalau split bill)
+	if len(order.Payments) > 0 {
+		pdf.SetFont("regular", "", 12)
+
+		paid := models.Money(0)
+		for i, payment := range order.Payments {
+			drawKeyValue(pdf, leftMargin, fmt.Sprintf("Bayar %d", i+1), paymentLabel(payment))
+			paid += payment.Amount
+		}
+		if remaining := order.Total + order.Rounding - paid; remaining > 0 {
+			drawKeyValue(pdf, leftMargin, "Sisa", remaining.String())
+		}
+		pdf.Br(10)
+	}
+
 	drawLine(pdf, leftMargin, pageWidth-rightMargin)
 	pdf.Br(20)
 
@@ -124,15 +188,37 @@ func (s *ReceiptService) GenerateReceipt(order *models.Order) (string, error) {
 		gopdf.CellOption{Align: gopdf.Center},
 	)
 
-	folder := "src/uploads/receipts"
-	_ = os.MkdirAll(folder, os.ModePerm)
-	filename := fmt.Sprintf("%s/receipt_%d.pdf", folder, order.ID)
+	var buf bytes.Buffer
+	if _, err := pdf.WriteTo(&buf); err != nil {
+		return "", err
+	}
 
-	if err := pdf.WritePdf(filename); err != nil {
+	key := fmt.Sprintf("receipts/receipt_%d.pdf", order.ID)
+	if err := s.Storage.Put(key, &buf, "application/pdf"); err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("uploads/receipts/receipt_%d.pdf", order.ID), nil
+	return s.Storage.URL(key), nil
+}
+
+func drawSummaryRow(pdf *gopdf.GoPdf, labelX, valueX float64, label string, amount models.Money) {
+	pdf.SetX(labelX)
+	pdf.Cell(nil, label)
+
+	pdf.SetX(valueX)
+	pdf.Cell(nil, amount.String())
+	pdf.Br(18)
+}
+
+func paymentLabel(payment models.Payment) string {
+	label := fmt.Sprintf("%s - %s", strings.ToUpper(payment.Method), payment.Amount)
+	switch payment.SplitType {
+	case "items":
+		label += fmt.Sprintf(" (%d item)", len(payment.Items))
+	case "equal":
+		label += fmt.Sprintf(" (split %d)", payment.Shares)
+	}
+	return label
 }
 
 func drawLine(pdf *gopdf.GoPdf, x1, x2 float64) {
@@ -146,8 +232,7 @@ func drawLineAt(pdf *gopdf.GoPdf, x1, x2, y float64) {
 	pdf.Line(x1, y, x2, y)
 }
 
-func drawTableHeader(pdf *gopdf.GoPdf, startX, startY float64, widths []float64) {
-	headers := []string{"Item", "Qty", "Harga", "Subtotal"}
+func drawTableHeader(pdf *gopdf.GoPdf, startX, startY float64, widths []float64, headers []string) {
 	rowHeight := 20.0
 
 	x := startX
@@ -2,12 +2,15 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"titik-rindang/src/events"
 	"titik-rindang/src/helper"
 	"titik-rindang/src/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReservationService struct {
@@ -24,28 +27,108 @@ func (s *ReservationService) CreateReservation(reservation *models.Reservation)
 	reservation.CreatedAt = time.Now()
 	reservation.UpdatedAt = time.Now()
 
-	var table models.Table
-	if err := s.DB.First(&table, reservation.TableID).Error; err != nil {
-		return errors.New("table not found")
+	if reservation.ReservationDate.Before(time.Now()) {
+		return errors.New("reservation time must be in the future")
 	}
-	if table.Status != "available" {
-		return errors.New("table is not available")
+	if reservation.PartySize <= 0 {
+		return errors.New("party size must be greater than 0")
+	}
+	if err := setReservationWindow(reservation); err != nil {
+		return err
 	}
 
 	reservation.TableFee = helper.GetReservationFee()
 
-	table.Status = "booked"
-	if err := s.DB.Save(&table).Error; err != nil {
+	// link pembayaran customer memakai token, bukan ID yang bisa ditebak
+	paymentToken, err := helper.RandomToken(16)
+	if err != nil {
 		return err
 	}
+	reservation.PaymentToken = paymentToken
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// lock row meja supaya dua booking bersamaan tidak lolos cek yang sama
+		var table models.Table
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&table, reservation.TableID).Error; err != nil {
+			return errors.New("table not found")
+		}
+		if !table.Fits(reservation.PartySize) {
+			return fmt.Errorf("table %d seats %d-%d guests", table.TableNo, table.MinCapacity, table.MaxCapacity)
+		}
+
+		available, err := NewAvailabilityService(tx).IsTableAvailable(table.ID, reservation.ReservationDate, reservation.EndDate, 0)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return errors.New("table is not available at that time")
+		}
 
-	if err := s.DB.Create(reservation).Error; err != nil {
+		return tx.Create(reservation).Error
+	})
+	if err != nil {
 		return err
 	}
 
+	events.Publish(events.TopicReservation, events.ReservationCreated, reservation)
+	return nil
+}
+
+// Isi Duration default dan hitung EndDate dari jam mulai
+func setReservationWindow(reservation *models.Reservation) error {
+	if reservation.Duration <= 0 {
+		reservation.Duration = int(helper.GetReservationDuration() / time.Minute)
+	}
+	if maxDuration := int(helper.GetMaxReservationDuration() / time.Minute); reservation.Duration > maxDuration {
+		return fmt.Errorf("duration must not exceed %d minutes", maxDuration)
+	}
+	reservation.EndDate = reservation.ReservationDate.Add(time.Duration(reservation.Duration) * time.Minute)
 	return nil
 }
 
+var ErrReservationNotUnpaid = errors.New("only unpaid reservations can be confirmed")
+
+// Confirm Reservation: tandai Paid & siapkan invoice
+func (s *ReservationService) ConfirmReservation(reservation *models.Reservation) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := transactionWithEvents(s.DB, func(tx *gorm.DB) error {
+		// cek ulang status dengan lock, reservasi Paid / Cancelled tidak boleh dikonfirmasi lagi
+		var current models.Reservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, reservation.ID).Error; err != nil {
+			return errors.New("reservation not found")
+		}
+		if current.Status != "Unpaid" {
+			return fmt.Errorf("%w (reservation is %s)", ErrReservationNotUnpaid, current.Status)
+		}
+
+		reservation.Status = "Paid"
+		reservation.UpdatedAt = time.Now()
+		if err := tx.Save(reservation).Error; err != nil {
+			return err
+		}
+
+		newInvoice, err := NewInvoiceService(tx).CreateInvoice(reservation)
+		if err != nil {
+			return err
+		}
+
+		invoice = *newInvoice
+		invoice.PaymentMethod = "Paid"
+		if err := tx.Save(&invoice).Error; err != nil {
+			return err
+		}
+
+		// dikirim setelah commit, juga kalau dipanggil dari transaksi webhook
+		publishAfterCommit(tx, events.TopicReservation, events.ReservationConfirmed, reservation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
 // Get All Reservations
 func (s *ReservationService) GetAllReservations() ([]models.Reservation, error) {
 	var reservations []models.Reservation
@@ -66,54 +149,94 @@ func (s *ReservationService) GetReservationByID(id uint) (*models.Reservation, e
 	return &reservation, nil
 }
 
-// Update Reservation
-func (s *ReservationService) UpdateReservation(id uint, updatedData *models.Reservation) (*models.Reservation, error) {
-	reservation, err := s.GetReservationByID(id)
+// Get Reservation by payment token (customer)
+func (s *ReservationService) GetReservationByToken(token string) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := s.DB.Preload("Table").Where("payment_token = ?", token).First(&reservation).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("reservation not found")
+		}
 		return nil, err
 	}
+	return &reservation, nil
+}
 
-	if updatedData.Status != "" {
-		reservation.Status = updatedData.Status
-
-		// if reservations completed/cancelled, table must available
-		if updatedData.Status == "completed" || updatedData.Status == "cancelled" {
-			var table models.Table
-			if err := s.DB.First(&table, reservation.TableID).Error; err == nil {
-				table.Status = "available"
-				s.DB.Save(&table)
+// Update Reservation
+func (s *ReservationService) UpdateReservation(id uint, updatedData *models.Reservation) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("reservation not found")
 			}
+			return err
+		}
+
+		// reservasi batal yang diaktifkan lagi harus dicek ulang seperti booking baru
+		reactivated := false
+		if updatedData.Status != "" {
+			reactivated = reservationReleased(reservation.Status) && !reservationReleased(updatedData.Status)
+			reservation.Status = updatedData.Status
 		}
-	}
 
-	// Update table if moved
-	if updatedData.TableID != 0 && updatedData.TableID != reservation.TableID {
-		var newTable models.Table
-		if err := s.DB.First(&newTable, updatedData.TableID).Error; err != nil {
-			return nil, errors.New("new table not found")
+		// Reschedule / pindah meja harus dicek ulang ke jadwal meja
+		moved := reactivated
+		rescheduled := reactivated
+		if !updatedData.ReservationDate.IsZero() && !updatedData.ReservationDate.Equal(reservation.ReservationDate) {
+			reservation.ReservationDate = updatedData.ReservationDate
+			moved = true
+			rescheduled = true
+		}
+		if rescheduled && reservation.ReservationDate.Before(time.Now()) {
+			return errors.New("reservation time must be in the future")
 		}
-		if newTable.Status != "available" {
-			return nil, errors.New("new table is not available")
+		if updatedData.Duration > 0 && updatedData.Duration != reservation.Duration {
+			reservation.Duration = updatedData.Duration
+			moved = true
+		}
+		if updatedData.TableID != 0 && updatedData.TableID != reservation.TableID {
+			reservation.TableID = updatedData.TableID
+			moved = true
+		}
+		if updatedData.PartySize > 0 && updatedData.PartySize != reservation.PartySize {
+			reservation.PartySize = updatedData.PartySize
+			moved = true
 		}
 
-		// Bebaskan meja lama
-		var oldTable models.Table
-		_ = s.DB.First(&oldTable, reservation.TableID)
-		oldTable.Status = "available"
-		s.DB.Save(&oldTable)
+		// lock row meja (sama seperti CreateReservation) supaya dua update bersamaan tidak lolos cek yang sama
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation.Table, reservation.TableID).Error; err != nil {
+			if updatedData.TableID != 0 {
+				return errors.New("new table not found")
+			}
+			return err
+		}
 
-		// Pakai meja baru
-		newTable.Status = "booked"
-		s.DB.Save(&newTable)
+		if moved {
+			if !reservation.Table.Fits(reservation.PartySize) {
+				return fmt.Errorf("table %d seats %d-%d guests", reservation.Table.TableNo, reservation.Table.MinCapacity, reservation.Table.MaxCapacity)
+			}
 
-		reservation.TableID = updatedData.TableID
-	}
+			if err := setReservationWindow(&reservation); err != nil {
+				return err
+			}
 
-	reservation.UpdatedAt = time.Now()
-	if err := s.DB.Save(reservation).Error; err != nil {
+			available, err := NewAvailabilityService(tx).IsTableAvailable(reservation.TableID, reservation.ReservationDate, reservation.EndDate, reservation.ID)
+			if err != nil {
+				return err
+			}
+			if !available {
+				return errors.New("table is not available at that time")
+			}
+		}
+
+		reservation.UpdatedAt = time.Now()
+		return tx.Omit("Table").Save(&reservation).Error
+	})
+	if err != nil {
 		return nil, err
 	}
-	return reservation, nil
+	return &reservation, nil
 }
 
 // Delete Reservation
@@ -126,12 +249,6 @@ func (s *ReservationService) DeleteReservation(id uint) error {
 
 	s.DB.Where("reservation_id = ?", reservation.ID).Delete(&models.Invoice{})
 
-	var table models.Table
-	if err := s.DB.First(&table, reservation.TableID).Error; err == nil {
-		table.Status = "available"
-		s.DB.Save(&table)
-	}
-
 	result := s.DB.Delete(&models.Reservation{}, id)
 	if result.RowsAffected == 0 {
 		return errors.New("reservation not found")
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"titik-rindang/src/models"
+)
+
+func TestConfirmReservationOnlyOnce(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+
+	svc := NewReservationService(db)
+	reservation := models.Reservation{
+		Name:            "Sari",
+		Phone:           "08123456789",
+		TableID:         table.ID,
+		PartySize:       2,
+		ReservationDate: time.Now().Add(48 * time.Hour),
+	}
+	if err := svc.CreateReservation(&reservation); err != nil {
+		t.Fatalf("CreateReservation: %v", err)
+	}
+
+	if _, err := svc.ConfirmReservation(&reservation); err != nil {
+		t.Fatalf("first ConfirmReservation: %v", err)
+	}
+	if _, err := svc.ConfirmReservation(&reservation); !errors.Is(err, ErrReservationNotUnpaid) {
+		t.Fatalf("second ConfirmReservation error = %v, want ErrReservationNotUnpaid", err)
+	}
+
+	// reservasi batal juga tidak bisa dikonfirmasi
+	cancelled := models.Reservation{
+		Name:            "Tono",
+		Phone:           "08129876543",
+		TableID:         table.ID,
+		PartySize:       2,
+		ReservationDate: time.Now().Add(96 * time.Hour),
+	}
+	if err := svc.CreateReservation(&cancelled); err != nil {
+		t.Fatalf("CreateReservation: %v", err)
+	}
+	db.Model(&cancelled).Update("status", "Cancelled")
+	if _, err := svc.ConfirmReservation(&cancelled); !errors.Is(err, ErrReservationNotUnpaid) {
+		t.Fatalf("ConfirmReservation on cancelled error = %v, want ErrReservationNotUnpaid", err)
+	}
+
+	if n := countRows(t, db, &models.Invoice{}); n != 1 {
+		t.Errorf("invoices rows = %d, want 1", n)
+	}
+}
+
+func TestUpdateReservationRechecksScheduleAndTime(t *testing.T) {
+	db := openTestDB(t)
+	table := createTestTable(t, db, 1)
+	svc := NewReservationService(db)
+
+	at := time.Now().Add(48 * time.Hour)
+	first := models.Reservation{Name: "Sari", Phone: "08123456789", TableID: table.ID, PartySize: 2, ReservationDate: at}
+	if err := svc.CreateReservation(&first); err != nil {
+		t.Fatalf("CreateReservation: %v", err)
+	}
+	if _, err := svc.UpdateReservation(first.ID, &models.Reservation{Status: "Cancelled"}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	// meja dipakai tamu lain setelah reservasi pertama batal
+	second := models.Reservation{Name: "Tono", Phone: "08129876543", TableID: table.ID, PartySize: 2, ReservationDate: at}
+	if err := svc.CreateReservation(&second); err != nil {
+		t.Fatalf("CreateReservation on freed slot: %v", err)
+	}
+
+	if _, err := svc.UpdateReservation(first.ID, &models.Reservation{Status: "Unpaid"}); err == nil {
+		t.Error("reactivating a cancelled reservation double-booked the table")
+	}
+	if _, err := svc.UpdateReservation(second.ID, &models.Reservation{ReservationDate: time.Now().Add(-time.Hour)}); err == nil {
+		t.Error("reservation was rescheduled into the past")
+	}
+
+	// tanpa bentrok, reservasi batal boleh diaktifkan lagi
+	if _, err := svc.UpdateReservation(first.ID, &models.Reservation{Status: "Unpaid", ReservationDate: at.Add(24 * time.Hour)}); err != nil {
+		t.Errorf("reactivate on a free slot: %v", err)
+	}
+}
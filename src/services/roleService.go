@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"titik-rindang/src/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleExists        = errors.New("role already exists")
+	ErrInvalidRoleName   = errors.New("role name may only contain lowercase letters, numbers, - and _")
+	ErrSystemRole        = errors.New("system role cannot be changed")
+	ErrRoleInUse         = errors.New("role is still assigned to users")
+	ErrUnknownPermission = errors.New("unknown permission")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9_-]{2,50}$`)
+
+// Cache role -> permission, dibuang setiap ada perubahan role
+var (
+	permCacheMu  sync.RWMutex
+	permCache    map[string]map[string]bool
+	permCacheGen int
+)
+
+type RoleService struct {
+	DB *gorm.DB
+}
+
+func NewRoleService(db *gorm.DB) *RoleService {
+	return &RoleService{DB: db}
+}
+
+type RoleInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// Sinkron katalog permission & role bawaan. Role bawaan yang sudah ada tidak ditimpa,
+// kecuali admin yang selalu mendapat semua permission.
+func (s *RoleService) SeedRoles() error {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var all []models.Permission
+		for _, p := range models.PermissionCatalog {
+			permission := models.Permission{Name: p.Name}
+			if err := tx.Where(models.Permission{Name: p.Name}).
+				Assign(models.Permission{Description: p.Description}).
+				FirstOrCreate(&permission).Error; err != nil {
+				return err
+			}
+			all = append(all, permission)
+		}
+
+		for _, name := range []string{models.RoleAdmin, models.RoleCashier, models.RoleStaff} {
+			var role models.Role
+			err := tx.Where("name = ?", name).First(&role).Error
+			if err == nil {
+				if !role.IsSystem {
+					tx.Model(&role).Update("is_system", true)
+				}
+				if name == models.RoleAdmin {
+					if err := tx.Model(&role).Association("Permissions").Replace(all); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			role = models.Role{Name: name, IsSystem: true, Permissions: all}
+			if name != models.RoleAdmin {
+				role.Permissions = filterPermissions(all, models.DefaultRolePermissions[name])
+			}
+			if err := tx.Create(&role).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	invalidatePermissionCache()
+	return err
+}
+
+func filterPermissions(all []models.Permission, names []string) []models.Permission {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var result []models.Permission
+	for _, p := range all {
+		if wanted[p.Name] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func (s *RoleService) GetAllRoles() ([]models.Role, error) {
+	var roles []models.Role
+	err := s.DB.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC")
+	}).Order("is_system DESC, name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (s *RoleService) GetAllPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := s.DB.Order("name ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (s *RoleService) RoleExists(name string) bool {
+	var count int64
+	s.DB.Model(&models.Role{}).Where("name = ?", name).Count(&count)
+	return count > 0
+}
+
+func (s *RoleService) CreateRole(input RoleInput) (*models.Role, error) {
+	name := strings.ToLower(strings.TrimSpace(input.Name))
+	if !roleNamePattern.MatchString(name) {
+		return nil, ErrInvalidRoleName
+	}
+	if s.RoleExists(name) {
+		return nil, ErrRoleExists
+	}
+
+	permissions, err := s.resolvePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := models.Role{
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		Permissions: permissions,
+	}
+	if err := s.DB.Create(&role).Error; err != nil {
+		return nil, err
+	}
+
+	invalidatePermissionCache()
+	return &role, nil
+}
+
+// Ubah deskripsi & permission role. Nama role tetap (dipakai di data user & token).
+func (s *RoleService) UpdateRole(id uint, input RoleInput) (*models.Role, error) {
+	var role models.Role
+	if err := s.DB.First(&role, id).Error; err != nil {
+		return nil, ErrRoleNotFound
+	}
+	if role.Name == models.RoleAdmin {
+		return nil, ErrSystemRole
+	}
+
+	permissions, err := s.resolvePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&role).Update("description", strings.TrimSpace(input.Description)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&role).Association("Permissions").Replace(permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidatePermissionCache()
+	role.Permissions = permissions
+	return &role, nil
+}
+
+func (s *RoleService) DeleteRole(id uint) error {
+	var role models.Role
+	if err := s.DB.First(&role, id).Error; err != nil {
+		return ErrRoleNotFound
+	}
+	if role.IsSystem {
+		return ErrSystemRole
+	}
+
+	var users int64
+	s.DB.Model(&models.Auth{}).Where("role = ?", role.Name).Count(&users)
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&role).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidatePermissionCache()
+	return nil
+}
+
+func (s *RoleService) resolvePermissions(names []string) ([]models.Permission, error) {
+	permissions := []models.Permission{}
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	if err := s.DB.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	for _, p := range permissions {
+		found[p.Name] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, name)
+		}
+	}
+	return permissions, nil
+}
+
+// Cek permission role dari cache (dimuat ulang dari database kalau kosong)
+func (s *RoleService) HasPermission(role, permission string) bool {
+	return s.permissionSet(role)[permission]
+}
+
+// Daftar permission role, terurut
+func (s *RoleService) PermissionsOf(role string) []string {
+	list := []string{}
+	for name := range s.permissionSet(role) {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func (s *RoleService) permissionSet(role string) map[string]bool {
+	permCacheMu.RLock()
+	cache, gen := permCache, permCacheGen
+	permCacheMu.RUnlock()
+
+	if cache == nil {
+		var roles []models.Role
+		if err := s.DB.Preload("Permissions").Find(&roles).Error; err != nil {
+			return nil
+		}
+
+		cache = map[string]map[string]bool{}
+		for _, r := range roles {
+			set := map[string]bool{}
+			for _, p := range r.Permissions {
+				set[p.Name] = true
+			}
+			cache[r.Name] = set
+		}
+
+		// jangan simpan kalau role berubah selama query berjalan
+		permCacheMu.Lock()
+		if gen == permCacheGen {
+			permCache = cache
+		}
+		permCacheMu.Unlock()
+	}
+	return cache[role]
+}
+
+func invalidatePermissionCache() {
+	permCacheMu.Lock()
+	permCache = nil
+	permCacheGen++
+	permCacheMu.Unlock()
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"errors"
+	"slices"
+	"strings"
+	"titik-rindang/src/events"
 	"titik-rindang/src/models"
 
 	"gorm.io/gorm"
@@ -20,10 +23,35 @@ func (s *TableService) CreateTable(table *models.Table) error {
 	if table.TableNo <= 0 {
 		return errors.New("table number must be greater than 0")
 	}
+	if table.MinCapacity == 0 {
+		table.MinCapacity = 1
+	}
+	if table.MaxCapacity == 0 {
+		table.MaxCapacity = 4
+	}
+	if table.Zone == "" {
+		table.Zone = "indoor"
+	}
+	if err := validateTableSeating(table); err != nil {
+		return err
+	}
 	table.Status = "available"
 	return s.DB.Create(table).Error
 }
 
+func validateTableSeating(table *models.Table) error {
+	if table.MinCapacity < 1 {
+		return errors.New("minimum capacity must be at least 1")
+	}
+	if table.MaxCapacity < table.MinCapacity {
+		return errors.New("maximum capacity must not be less than minimum capacity")
+	}
+	if !slices.Contains(models.TableZones, table.Zone) {
+		return errors.New("zone must be one of: " + strings.Join(models.TableZones, ", "))
+	}
+	return nil
+}
+
 // Get All Tables
 func (s *TableService) GetAllTables() ([]models.Table, error) {
 	var tables []models.Table
@@ -51,12 +79,35 @@ func (s *TableService) UpdateTable(id uint, updatedData *models.Table) (*models.
 	if updatedData.TableNo > 0 {
 		table.TableNo = updatedData.TableNo
 	}
-	if updatedData.Status != "" {
+	statusChanged := false
+	if updatedData.Status != "" && updatedData.Status != table.Status {
 		table.Status = updatedData.Status
+		statusChanged = true
+	}
+	if updatedData.MinCapacity > 0 {
+		table.MinCapacity = updatedData.MinCapacity
+	}
+	if updatedData.MaxCapacity > 0 {
+		table.MaxCapacity = updatedData.MaxCapacity
+	}
+	if updatedData.Zone != "" {
+		table.Zone = updatedData.Zone
+	}
+	if updatedData.Features != nil {
+		table.Features = updatedData.Features
+	}
+	if err := validateTableSeating(table); err != nil {
+		return nil, err
 	}
 
-	err = s.DB.Save(table).Error
-	return table, err
+	if err := s.DB.Save(table).Error; err != nil {
+		return nil, err
+	}
+
+	if statusChanged {
+		events.Publish(events.TopicTable, events.TableStatusChanged, table)
+	}
+	return table, nil
 }
 
 // Delete Table
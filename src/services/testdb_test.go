@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"titik-rindang/src/database"
+	"titik-rindang/src/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	testDBOnce     sync.Once
+	testDB         *gorm.DB
+	testDBErr      error
+	skippedDBTests atomic.Int32
+)
+
+// Ringkasan di akhir supaya test database yang dilewati tidak hilang begitu saja di output go test
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if n := skippedDBTests.Load(); n > 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: %d database tests in services were skipped, set TEST_DATABASE_URL to run them\n", n)
+	}
+	os.Exit(code)
+}
+
+// Postgres khusus test dari TEST_DATABASE_URL, test dilewati kalau tidak di-set.
+// Di CI (CI atau REQUIRE_DB_TESTS di-set) test gagal kalau database tidak tersedia.
+// Semua tabel dikosongkan di awal setiap test, jangan arahkan ke database yang berisi data asli.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		if os.Getenv("REQUIRE_DB_TESTS") != "" || os.Getenv("CI") != "" {
+			t.Fatal("TEST_DATABASE_URL must be set when CI or REQUIRE_DB_TESTS is set")
+		}
+		skippedDBTests.Add(1)
+		t.Skip("TEST_DATABASE_URL not set, skipping database test")
+	}
+
+	testDBOnce.Do(func() {
+		testDB, testDBErr = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if testDBErr == nil {
+			testDBErr = database.Migrate(testDB)
+		}
+	})
+	if testDBErr != nil {
+		t.Fatalf("connect test database: %v", testDBErr)
+	}
+
+	var tables []string
+	if err := testDB.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema()").Scan(&tables).Error; err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	if len(tables) > 0 {
+		// TRUNCATE tidak memicu trigger per baris, jadi audit_logs juga ikut kosong
+		if err := testDB.Exec("TRUNCATE " + strings.Join(quoteIdents(tables), ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+			t.Fatalf("truncate tables: %v", err)
+		}
+	}
+	return testDB
+}
+
+func quoteIdents(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return quoted
+}
+
+func createTestTable(t *testing.T, db *gorm.DB, tableNo int) models.Table {
+	t.Helper()
+	table := models.Table{TableNo: tableNo, Status: "available", MinCapacity: 1, MaxCapacity: 4}
+	if err := db.Create(&table).Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	return table
+}
+
+func createTestMenu(t *testing.T, db *gorm.DB, name string, price models.Money) models.Menu {
+	t.Helper()
+	menu := models.Menu{Name: name, Price: price, Station: "kitchen", IsAvailable: true}
+	if err := db.Create(&menu).Error; err != nil {
+		t.Fatalf("create menu: %v", err)
+	}
+	return menu
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	return count
+}
+
+// Dapur menyiapkan semua item sampai ready, lalu order ditandai served
+func serveTestOrder(t *testing.T, db *gorm.DB, orderID uint) *models.Order {
+	t.Helper()
+	var items []models.OrderItem
+	if err := db.Where("order_id = ? AND prep_status <> ?", orderID, models.PrepVoided).Find(&items).Error; err != nil {
+		t.Fatalf("load order items: %v", err)
+	}
+	kitchen := NewKitchenService(db)
+	for _, item := range items {
+		for _, status := range []string{models.PrepPreparing, models.PrepReady} {
+			if _, err := kitchen.UpdateItemStatus(item.ID, status, "", "tester"); err != nil {
+				t.Fatalf("item %d -> %s: %v", item.ID, status, err)
+			}
+		}
+	}
+	order, err := NewOrderService(db).ChangeStatus(orderID, models.OrderServed, "tester", "")
+	if err != nil {
+		t.Fatalf("serve order: %v", err)
+	}
+	return order
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"titik-rindang/src/events"
+
+	"gorm.io/gorm"
+)
+
+// Event yang dibuat di dalam transaksi ditahan dulu dan baru dikirim setelah commit,
+// supaya client tidak menerima perubahan yang akhirnya di-rollback.
+const pendingEventsKey = "services:pending_events"
+
+type pendingEvent struct {
+	topic     string
+	eventType string
+	data      interface{}
+}
+
+type pendingEvents struct {
+	list []pendingEvent
+}
+
+// Jalankan fn dalam transaksi. Event dari publishAfterCommit dikirim setelah commit,
+// atau diteruskan ke transaksi luar kalau ini transaksi bersarang (savepoint).
+// Kalau fn gagal, event-nya dibuang.
+func transactionWithEvents(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	parent, nested := db.Get(pendingEventsKey)
+	pending := &pendingEvents{}
+
+	if err := db.Set(pendingEventsKey, pending).Transaction(fn); err != nil {
+		return err
+	}
+
+	if nested {
+		outer := parent.(*pendingEvents)
+		outer.list = append(outer.list, pending.list...)
+		return nil
+	}
+	for _, event := range pending.list {
+		events.Publish(event.topic, event.eventType, event.data)
+	}
+	return nil
+}
+
+// Kirim event setelah transaksi tx commit, langsung dikirim kalau tx bukan dari transactionWithEvents
+func publishAfterCommit(tx *gorm.DB, topic, eventType string, data interface{}) {
+	if value, ok := tx.Get(pendingEventsKey); ok {
+		pending := value.(*pendingEvents)
+		pending.list = append(pending.list, pendingEvent{topic: topic, eventType: eventType, data: data})
+		return
+	}
+	events.Publish(topic, eventType, data)
+}
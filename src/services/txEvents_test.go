@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"titik-rindang/src/events"
+	"titik-rindang/src/models"
+
+	"gorm.io/gorm"
+)
+
+// Tipe event inventory yang sudah terkirim ke subscriber
+func drainInventoryEvents(ch <-chan events.Event) []string {
+	var types []string
+	for {
+		select {
+		case event := <-ch:
+			types = append(types, event.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestEventsArePublishedOnlyAfterCommit(t *testing.T) {
+	db := openTestDB(t)
+	ch, unsubscribe := events.Subscribe(events.Filter{Permissions: []string{models.PermInventoryView}})
+	defer unsubscribe()
+
+	ingredient := models.Ingredient{Name: "Susu", Unit: "ml", Stock: 1000, LowStockThreshold: 200}
+	if err := db.Create(&ingredient).Error; err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	errAbort := errors.New("abort")
+
+	// stok menipis lalu transaksi batal: tidak ada event
+	err := transactionWithEvents(db, func(tx *gorm.DB) error {
+		if _, err := changeStock(tx, ingredient.ID, -900, models.StockWaste, "", nil, "tester"); err != nil {
+			return err
+		}
+		if got := drainInventoryEvents(ch); len(got) != 0 {
+			t.Errorf("events sent before commit: %v", got)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("transaction error = %v, want abort", err)
+	}
+	if got := drainInventoryEvents(ch); len(got) != 0 {
+		t.Errorf("events sent for rolled back change: %v", got)
+	}
+
+	// savepoint gagal di dalam transaksi yang commit: event savepoint dibuang
+	err = transactionWithEvents(db, func(tx *gorm.DB) error {
+		innerErr := transactionWithEvents(tx, func(tx *gorm.DB) error {
+			if _, err := changeStock(tx, ingredient.ID, -900, models.StockWaste, "", nil, "tester"); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(innerErr, errAbort) {
+			t.Errorf("savepoint error = %v, want abort", innerErr)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if got := drainInventoryEvents(ch); len(got) != 0 {
+		t.Errorf("events sent for rolled back savepoint: %v", got)
+	}
+
+	// savepoint berhasil: event ikut terkirim setelah transaksi luar commit
+	err = transactionWithEvents(db, func(tx *gorm.DB) error {
+		return transactionWithEvents(tx, func(tx *gorm.DB) error {
+			_, err := changeStock(tx, ingredient.ID, -900, models.StockWaste, "", nil, "tester")
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if got := drainInventoryEvents(ch); len(got) != 1 || got[0] != events.InventoryLowStock {
+		t.Errorf("events after commit = %v, want [%s]", got, events.InventoryLowStock)
+	}
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// Penyimpanan file upload (gambar menu, struk), bisa diganti lewat STORAGE_DRIVER.
+// Key selalu berupa path relatif dengan pemisah "/", contoh: menu/abc_full.jpg
+type Storage interface {
+	Put(key string, r io.Reader, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	URL(key string) string
+}
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+func NewStorage(driver string) (Storage, error) {
+	switch driver {
+	case "", "local":
+		return NewLocalStorage(envOr("STORAGE_LOCAL_ROOT", "src/uploads"), envOr("STORAGE_LOCAL_URL", "/uploads")), nil
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    envOr("S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+	default:
+		return nil, errors.New("unknown storage driver: " + driver)
+	}
+}
+
+var defaultStorage Storage
+
+// Storage sesuai env, fallback ke folder lokal
+func Default() Storage {
+	if defaultStorage != nil {
+		return defaultStorage
+	}
+	s, err := NewStorage(os.Getenv("STORAGE_DRIVER"))
+	if err != nil {
+		log.Printf("%v, falling back to local storage", err)
+		s = NewLocalStorage("src/uploads", "/uploads")
+	}
+	defaultStorage = s
+	return s
+}
+
+// Ambil key dari URL yang pernah dikembalikan s.URL, false kalau bukan milik storage ini
+func KeyFromURL(s Storage, rawURL string) (string, bool) {
+	prefix := s.URL("")
+	if !strings.HasPrefix(rawURL, prefix) {
+		return "", false
+	}
+	key, err := url.PathUnescape(strings.TrimPrefix(rawURL, prefix))
+	if err != nil {
+		return "", false
+	}
+	key, err = cleanKey(key)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// Tolak key kosong / keluar folder (../)
+func cleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}